@@ -11,13 +11,17 @@ require (
 	gorm.io/gorm v1.25.12
 	riccardotornesello.it/sharedtelemetry/iracing/cars_models v0.0.0-00010101000000-000000000000
 	riccardotornesello.it/sharedtelemetry/iracing/events_models v0.0.0-00010101000000-000000000000
+	riccardotornesello.it/sharedtelemetry/iracing/firestore v0.0.0-00010101000000-000000000000
 	riccardotornesello.it/sharedtelemetry/iracing/gorm_utils v0.0.0-00010101000000-000000000000
+	riccardotornesello.it/sharedtelemetry/iracing/irapi v0.0.0-00010101000000-000000000000
 )
 
 replace (
 	riccardotornesello.it/sharedtelemetry/iracing/cars_models => ../../libs/cars_models
 	riccardotornesello.it/sharedtelemetry/iracing/events_models => ../../libs/events_models
+	riccardotornesello.it/sharedtelemetry/iracing/firestore => ../../libs/iracing/firestore_go
 	riccardotornesello.it/sharedtelemetry/iracing/gorm_utils => ../../libs/gorm_utils
+	riccardotornesello.it/sharedtelemetry/iracing/irapi => ../../libs/irapi
 )
 
 require (
@@ -12,6 +12,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"riccardotornesello.it/sharedtelemetry/iracing/api/logic"
+	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
 )
 
 type RankingResponse struct {
@@ -97,6 +99,23 @@ type SessionSimsession struct {
 type SessionSimsessionParticipant struct {
 	CustID int `firestore:"custId"`
 	CarID  int `firestore:"carId"`
+	TeamID int `firestore:"teamId"` // Only in team events
+
+	Laps []*Lap `firestore:"laps"` // Only in the sessions saved before the participants subcollection
+}
+
+// EntryID is the id of the participant document: the team for team events, else the driver
+func (p *SessionSimsessionParticipant) EntryID() int {
+	if p.TeamID != 0 {
+		return p.TeamID
+	}
+	return p.CustID
+}
+
+// The laps of a participant, in the simsessions/{simsessionNumber}/participants subcollection of the session
+type SessionParticipantLaps struct {
+	CustID int `firestore:"custId"`
+	CarID  int `firestore:"carId"`
 
 	Laps []*Lap `firestore:"laps"`
 }
@@ -339,7 +358,7 @@ func CompetitionRankingHandler(c *gin.Context, firestoreClient *firestore.Client
 }
 
 func getGroupSessions(trackId int, dateStr string, driverCars map[int]int, firestoreClient *firestore.Client, firestoreContext context.Context) (map[int]int, error) {
-	db := firestoreClient.Collection("iracing_sessions")
+	db := firestoreClient.Collection(firestore_structs.SessionsCollection)
 
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
@@ -368,19 +387,50 @@ func getGroupSessions(trackId int, dateStr string, driverCars map[int]int, fires
 
 		for _, simsession := range session.Simsessions {
 			// TODO: variable simsession types
-			if simsession.SimsessionName != "QUALIFY" {
+			if !irapi.SimsessionType(simsession.SimsessionType).IsQualifying() {
 				continue
 			}
 
+			// Read only the laps of the participants with an allowed car
+			participantsCollection := doc.Ref.
+				Collection(firestore_structs.SessionSimsessionsCollection).
+				Doc(firestore_structs.SessionSimsessionID(simsession.SimsessionNumber)).
+				Collection(firestore_structs.SessionParticipantsCollection)
+
+			participants := make([]*SessionSimsessionParticipant, 0, len(simsession.Participants))
+			participantRefs := make([]*firestore.DocumentRef, 0, len(simsession.Participants))
 			for _, participant := range simsession.Participants {
-				// Check if the car is allowed
 				if carId, ok := driverCars[participant.CustID]; !ok || carId != participant.CarID {
 					continue
 				}
+				participants = append(participants, participant)
+				participantRefs = append(participantRefs, participantsCollection.Doc(firestore_structs.SessionParticipantID(participant.EntryID())))
+			}
+			if len(participantRefs) == 0 {
+				continue
+			}
+
+			participantDocs, err := firestoreClient.GetAll(firestoreContext, participantRefs)
+			if err != nil {
+				return nil, fmt.Errorf("error querying Firestore: %v", err)
+			}
+
+			for i, participantDoc := range participantDocs {
+				participant := participants[i]
+
+				// The sessions saved before the participants subcollection have the laps in the session document
+				laps := participant.Laps
+				if participantDoc.Exists() {
+					var participantLaps SessionParticipantLaps
+					if err := participantDoc.DataTo(&participantLaps); err != nil {
+						return nil, fmt.Errorf("error decoding document: %v", err)
+					}
+					laps = participantLaps.Laps
+				}
 
 				// Get the average time of the stint
 				// TODO: variable stint length
-				averageTime := getLapsAverage(participant.Laps, 3)
+				averageTime := getLapsAverage(laps, 3)
 
 				if averageTime > 0 {
 					if bestTime, ok := groupBestResults[participant.CustID]; !ok {
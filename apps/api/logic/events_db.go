@@ -24,7 +24,7 @@ func GetCompetitionSessions(db *gorm.DB, competitionId uint) ([]*CompetitionSess
 		Joins("join event_groups on sessions.track_id = event_groups.i_racing_track_id and text(date(sessions.launch_at)) = ANY(event_groups.dates)").
 		Joins("join competitions on competitions.id = event_groups.competition_id").
 		Where("event_groups.competition_id = ?", competitionId).
-		Where("session_simsessions.simsession_name = ?", "QUALIFY").
+		Where("session_simsessions.simsession_type IN ?", QualifyingSimsessionTypes).
 		Where("sessions.league_id = competitions.league_id").
 		Where("sessions.season_id = competitions.season_id").
 		Order("event_groups.id, sessions.launch_at").
@@ -56,13 +56,12 @@ func GetCompetitionClasses(db *gorm.DB, competitionId uint) ([]*events_models.Co
 }
 
 func GetEventGroupSessions(db *gorm.DB, trackId int, sessionDate string, leagueId int, seasonId int) ([]*events_models.SessionSimsession, error) {
-	simsessionName := "QUALIFY"
 	simsessionNumber := 0
 
 	var simsessions []*events_models.SessionSimsession
 	err := db.
 		Joins("Session").
-		Where("session_simsessions.simsession_name = ?", simsessionName).
+		Where("session_simsessions.simsession_type IN ?", QualifyingSimsessionTypes).
 		Where("session_simsessions.simsession_number = ?", simsessionNumber).
 		Where("\"Session\".track_id = ?", trackId).
 		Where("date(\"Session\".launch_at) = ?", sessionDate).
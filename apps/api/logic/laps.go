@@ -1,6 +1,26 @@
 package logic
 
-import "github.com/lib/pq"
+import (
+	"slices"
+
+	"github.com/lib/pq"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// Events invalidating a lap for the rankings
+var blacklistedLapEvents = []irapi.LapEvent{
+	irapi.LapEventBlackFlag,
+	irapi.LapEventCarContact,
+	irapi.LapEventCarReset,
+	irapi.LapEventClockSmash,
+	irapi.LapEventContact,
+	irapi.LapEventDiscontinuity,
+	irapi.LapEventInterpolatedCrossing,
+	irapi.LapEventInvalid,
+	irapi.LapEventLostControl,
+	irapi.LapEventOffTrack,
+	irapi.LapEventPitted,
+}
 
 func IsLapValid(lapNumber int, lapTime int, lapEvents pq.StringArray, incident bool) bool {
 	if !(lapNumber > 0 && lapTime > 0 && incident == false) {
@@ -8,25 +28,9 @@ func IsLapValid(lapNumber int, lapTime int, lapEvents pq.StringArray, incident b
 	}
 
 	// Return false if lap.lapEvents contains a blacklisted event
-	blacklistedEvents := []string{
-		"black flag",
-		"car contact",
-		"car reset",
-		"clock smash",
-		"contact",
-		"discontinuity",
-		"interpolated crossing",
-		"invalid",
-		"lost control",
-		"off track",
-		"pitted",
-	}
-
-	for _, event := range lapEvents {
-		for _, blacklistedEvent := range blacklistedEvents {
-			if event == blacklistedEvent {
-				return false
-			}
+	for _, event := range irapi.ParseLapEvents(lapEvents) {
+		if slices.Contains(blacklistedLapEvents, event) {
+			return false
 		}
 	}
 
@@ -34,11 +38,11 @@ func IsLapValid(lapNumber int, lapTime int, lapEvents pq.StringArray, incident b
 }
 
 func IsLapPitted(lapEvents pq.StringArray) bool {
-	for _, event := range lapEvents {
-		if event == "pitted" {
-			return true
-		}
-	}
+	return slices.Contains(irapi.ParseLapEvents(lapEvents), irapi.LapEventPitted)
+}
 
-	return false
+// Simsession types used for the qualifying rankings
+var QualifyingSimsessionTypes = []irapi.SimsessionType{
+	irapi.SimsessionTypeLoneQualifying,
+	irapi.SimsessionTypeOpenQualifying,
 }
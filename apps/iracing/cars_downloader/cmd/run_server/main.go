@@ -17,8 +17,17 @@ func main() {
 	// Get configuration
 	godotenv.Load()
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+	iRacingAuthConfig := irapi.AuthConfig{
+		Method:       os.Getenv("IRACING_AUTH_METHOD"),
+		Email:        os.Getenv("IRACING_EMAIL"),
+		Password:     os.Getenv("IRACING_PASSWORD"),
+		ClientID:     os.Getenv("IRACING_CLIENT_ID"),
+		ClientSecret: os.Getenv("IRACING_CLIENT_SECRET"),
+		TokenURL:     os.Getenv("IRACING_TOKEN_URL"),
+	}
+
+	// Optional, to share the catalogue between the runs and during development
+	iRacingCacheDir := os.Getenv("IRACING_CACHE_DIR")
 
 	// Initialize database
 	log.Println("Connecting to database")
@@ -38,7 +47,21 @@ func main() {
 
 	// Initialize iRacing client
 	log.Println("Initializing iRacing client")
-	irClient, err := irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	iRacingAuth, err := irapi.NewAuthenticator(iRacingAuthConfig)
+	if err != nil {
+		log.Fatalf("irapi.NewAuthenticator: %v", err)
+	}
+
+	var irClientOptions []irapi.ClientOption
+	if iRacingCacheDir != "" {
+		cache, err := irapi.NewFileCache(iRacingCacheDir)
+		if err != nil {
+			log.Fatalf("irapi.NewFileCache: %v", err)
+		}
+		irClientOptions = append(irClientOptions, irapi.WithCache(cache))
+	}
+
+	irClient, err := irapi.NewIRacingApiClient(firestoreContext, iRacingAuth, irClientOptions...)
 	if err != nil {
 		log.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
@@ -47,7 +70,7 @@ func main() {
 	// Start the job
 	log.Println("Starting job")
 
-	cars, carClasses, err := logic.FetchCars(irClient)
+	cars, carClasses, err := logic.FetchCars(firestoreContext, irClient)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"strconv"
 
 	firebase "firebase.google.com/go"
 	"github.com/joho/godotenv"
@@ -14,14 +15,37 @@ import (
 const projectID = "sharedtelemetryapp" // TODO: move to env
 
 func main() {
+	var err error
+
 	// Get configuration
 	godotenv.Load()
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+	iRacingAuthConfig := irapi.AuthConfig{
+		Method:       os.Getenv("IRACING_AUTH_METHOD"),
+		Email:        os.Getenv("IRACING_EMAIL"),
+		Password:     os.Getenv("IRACING_PASSWORD"),
+		ClientID:     os.Getenv("IRACING_CLIENT_ID"),
+		ClientSecret: os.Getenv("IRACING_CLIENT_SECRET"),
+		TokenURL:     os.Getenv("IRACING_TOKEN_URL"),
+	}
 
+	// A category, or "all" to download every category in a single run
 	carClass := os.Getenv("CAR_CLASS")
 
+	options := logic.DefaultUpdateDriverStatsOptions
+	if batchSize := os.Getenv("BATCH_SIZE"); batchSize != "" {
+		options.BatchSize, err = strconv.Atoi(batchSize)
+		if err != nil || options.BatchSize <= 0 {
+			log.Fatalf("invalid BATCH_SIZE: %v", batchSize)
+		}
+	}
+	if parallelism := os.Getenv("PARALLELISM"); parallelism != "" {
+		options.Parallelism, err = strconv.Atoi(parallelism)
+		if err != nil || options.Parallelism <= 0 {
+			log.Fatalf("invalid PARALLELISM: %v", parallelism)
+		}
+	}
+
 	// Initialize database
 	log.Println("Connecting to database")
 	firestoreContext := context.Background()
@@ -40,7 +64,12 @@ func main() {
 
 	// Initialize iRacing client
 	log.Println("Initializing iRacing client")
-	irClient, err := irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	iRacingAuth, err := irapi.NewAuthenticator(iRacingAuthConfig)
+	if err != nil {
+		log.Fatalf("irapi.NewAuthenticator: %v", err)
+	}
+
+	irClient, err := irapi.NewIRacingApiClient(firestoreContext, iRacingAuth)
 	if err != nil {
 		log.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
@@ -48,7 +77,11 @@ func main() {
 
 	// Start the job
 	log.Println("Starting job for car class", carClass)
-	err = logic.UpdateDriverStatsByCategory(firestoreClient, firestoreContext, irClient, carClass)
+	if carClass == logic.AllCategories {
+		err = logic.UpdateAllDriverStats(firestoreClient, firestoreContext, irClient, options)
+	} else {
+		err = logic.UpdateDriverStatsByCategory(firestoreClient, firestoreContext, irClient, carClass, options)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
+)
+
+func checkpointDoc(firestoreClient *firestore.Client, name string) *firestore.DocumentRef {
+	return firestoreClient.Collection(firestore_structs.DriverStatsCheckpointsCollection).Doc(name)
+}
+
+// getCheckpoint returns nil if the previous run completed. The name is the category, or AllCategories.
+func getCheckpoint(ctx context.Context, firestoreClient *firestore.Client, name string) (*firestore_structs.DriverStatsCheckpoint, error) {
+	snap, err := checkpointDoc(firestoreClient, name).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint firestore_structs.DriverStatsCheckpoint
+	if err := snap.DataTo(&checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+func saveCheckpoint(ctx context.Context, firestoreClient *firestore.Client, name string, rows int, custId int) error {
+	_, err := checkpointDoc(firestoreClient, name).Set(ctx, firestore_structs.DriverStatsCheckpoint{
+		Rows:      rows,
+		CustID:    custId,
+		UpdatedAt: time.Now(),
+	})
+	return err
+}
+
+func deleteCheckpoint(ctx context.Context, firestoreClient *firestore.Client, name string) error {
+	_, err := checkpointDoc(firestoreClient, name).Delete(ctx)
+	return err
+}
@@ -2,66 +2,477 @@ package logic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
 	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
 )
 
-func UpdateDriverStatsByCategory(firestoreClient *firestore.Client, firestoreContext context.Context, irClient *irapi.IRacingApiClient, carClass string) error {
-	// Get the stats CSV
+// AllCategories as car class downloads the stats of every category in a single run
+const AllCategories = "all"
+
+// Options of UpdateDriverStatsByCategory and UpdateAllDriverStats
+type UpdateDriverStatsOptions struct {
+	// Drivers written by each BulkWriter. The progress is saved after each batch.
+	BatchSize int
+
+	// Batches written at the same time
+	Parallelism int
+}
+
+var DefaultUpdateDriverStatsOptions = UpdateDriverStatsOptions{
+	BatchSize:   500,
+	Parallelism: 4,
+}
+
+// driverEntry is a driver with the stats of the downloaded categories, the others are nil
+type driverEntry struct {
+	custId   int
+	name     string
+	location string
+	stats    firestore_structs.DriverStats
+}
+
+func newDriverEntry(category irapi.Category, record *irapi.DriverStatsRow) *driverEntry {
+	driver := &driverEntry{
+		custId:   record.CustId,
+		name:     record.Driver,
+		location: record.Location,
+	}
+	driver.setStats(category, record)
+	return driver
+}
+
+func (d *driverEntry) setStats(category irapi.Category, record *irapi.DriverStatsRow) {
+	d.stats.Set(category, &firestore_structs.DriverStatsDetails{
+		License: record.Class,
+		IRating: record.Irating,
+	})
+}
+
+type driverStatsBatch struct {
+	index   int
+	rows    int // Rows of the CSV up to the end of the batch
+	drivers []*driverEntry
+	changed int // Drivers written, the others didn't change
+}
+
+func UpdateDriverStatsByCategory(firestoreClient *firestore.Client, firestoreContext context.Context, irClient *irapi.IRacingApiClient, carClass string, options UpdateDriverStatsOptions) error {
+	category, err := irapi.ParseCategory(carClass)
+	if err != nil {
+		return err
+	}
+
+	// The progress of a previous run which didn't complete
+	checkpoint, err := getCheckpoint(firestoreContext, firestoreClient, string(category))
+	if err != nil {
+		return fmt.Errorf("error getting the checkpoint of %v: %w", category, err)
+	}
+
+	// Get the stats CSV. It's read while the batches are written, which takes longer than the
+	// request timeout of the client: the timeout only covers the response headers, not the body.
 	log.Println("Fetching drivers stats for car class", carClass)
-	driversCsv, err := NewDriversCsv(irClient, carClass)
+	driversCsv, skippedRows, err := openDriverStats(firestoreContext, irClient, category, checkpoint)
 	if err != nil {
 		return err
 	}
+	defer driversCsv.Close()
 	log.Println("Drivers stats fetched")
 
-	// Insert the drivers
-	for {
+	if skippedRows > 0 {
+		log.Printf("Resuming after row %d, cust id %d", skippedRows, checkpoint.CustID)
+	}
+
+	return writeDriverStats(firestoreClient, firestoreContext, string(category), skippedRows, options, func() (*driverEntry, error) {
 		record, err := driversCsv.Read()
+		if err != nil {
+			return nil, err
+		}
+		return newDriverEntry(category, record), nil
+	})
+}
+
+// UpdateAllDriverStats downloads the stats of all the categories at the same time,
+// and writes each driver once with the stats of all its categories.
+// The drivers are written by cust id, so a new run resumes after the last written one.
+func UpdateAllDriverStats(firestoreClient *firestore.Client, firestoreContext context.Context, irClient *irapi.IRacingApiClient, options UpdateDriverStatsOptions) error {
+	// The progress of a previous run which didn't complete
+	checkpoint, err := getCheckpoint(firestoreContext, firestoreClient, AllCategories)
+	if err != nil {
+		return fmt.Errorf("error getting the checkpoint of all the categories: %w", err)
+	}
+
+	log.Println("Fetching drivers stats for all the car classes")
+	drivers, err := downloadAllDriverStats(firestoreContext, irClient)
+	if err != nil {
+		return err
+	}
+	log.Printf("Drivers stats fetched, %d drivers", len(drivers))
+
+	skippedRows := 0
+	if checkpoint != nil {
+		skippedRows = sort.Search(len(drivers), func(i int) bool {
+			return drivers[i].custId > checkpoint.CustID
+		})
+		log.Printf("Resuming after cust id %d, %d drivers skipped", checkpoint.CustID, skippedRows)
+	}
+
+	next := skippedRows
+	return writeDriverStats(firestoreClient, firestoreContext, AllCategories, skippedRows, options, func() (*driverEntry, error) {
+		if next >= len(drivers) {
+			return nil, io.EOF
+		}
+		driver := drivers[next]
+		next++
+		return driver, nil
+	})
+}
+
+// downloadAllDriverStats reads the CSVs of the categories concurrently and merges them by driver, sorted by cust id
+func downloadAllDriverStats(ctx context.Context, irClient *irapi.IRacingApiClient) ([]*driverEntry, error) {
+	var driversMutex sync.Mutex
+	drivers := make(map[int]*driverEntry)
+
+	errs := make([]error, len(irapi.Categories))
+	var wg sync.WaitGroup
+	for i, category := range irapi.Categories {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			driversCsv, err := irClient.GetDriverStatsByCategory(ctx, category)
+			if err != nil {
+				errs[i] = fmt.Errorf("error fetching the drivers stats of %v: %w", category, err)
+				return
+			}
+			defer driversCsv.Close()
+
+			for {
+				record, err := driversCsv.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("error reading the drivers stats of %v: %w", category, err)
+					return
+				}
+
+				driversMutex.Lock()
+				if driver, ok := drivers[record.CustId]; ok {
+					driver.setStats(category, record)
+				} else {
+					drivers[record.CustId] = newDriverEntry(category, record)
+				}
+				driversMutex.Unlock()
+			}
+
+			log.Println("Drivers stats fetched for car class", category)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	sortedDrivers := make([]*driverEntry, 0, len(drivers))
+	for _, driver := range drivers {
+		sortedDrivers = append(sortedDrivers, driver)
+	}
+	slices.SortFunc(sortedDrivers, func(a, b *driverEntry) int {
+		return a.custId - b.custId
+	})
+
+	return sortedDrivers, nil
+}
+
+// writeDriverStats writes the drivers returned by next until io.EOF, in parallel batches.
+// After each batch the progress is saved in the checkpoint with the given name.
+func writeDriverStats(firestoreClient *firestore.Client, firestoreContext context.Context, checkpointName string, skippedRows int, options UpdateDriverStatsOptions, next func() (*driverEntry, error)) error {
+	// The date of the changes in the history
+	runAt := time.Now()
+
+	batchesChan := make(chan *driverStatsBatch, options.Parallelism)
+	resultsChan := make(chan *driverStatsBatch, options.Parallelism)
+	workersCtx, cancel := context.WithCancelCause(firestoreContext)
+	defer cancel(nil)
+
+	// Start the workers to write the batches
+	var workersWg sync.WaitGroup
+	for i := 0; i < options.Parallelism; i++ {
+		workersWg.Add(1)
+		go writeDriverStatsWorker(firestoreClient,
+			runAt,
+			batchesChan,
+			resultsChan,
+			workersCtx,
+			&workersWg,
+			cancel,
+		)
+	}
+
+	// Save the progress in background, up to the first batch not written yet
+	var outputWg sync.WaitGroup
+	outputWg.Add(1)
+	go func() {
+		defer outputWg.Done()
+
+		startedAt := time.Now()
+		read := 0
+		written := 0
+		nextIndex := 0
+		completed := make(map[int]*driverStatsBatch)
+
+		for batch := range resultsChan {
+			completed[batch.index] = batch
+
+			for {
+				batch, ok := completed[nextIndex]
+				if !ok {
+					break
+				}
+				delete(completed, nextIndex)
+				nextIndex++
+
+				lastCustId := batch.drivers[len(batch.drivers)-1].custId
+				if err := saveCheckpoint(workersCtx, firestoreClient, checkpointName, batch.rows, lastCustId); err != nil {
+					cancel(fmt.Errorf("error saving the checkpoint of %v: %w", checkpointName, err))
+					continue
+				}
+
+				read += len(batch.drivers)
+				written += batch.changed
+				log.Printf("Checked %d drivers of %v, %d changed, up to row %d (%.0f drivers/s)", read, checkpointName, written, batch.rows, float64(read)/time.Since(startedAt).Seconds())
+			}
+		}
+	}()
+
+	// Send the batches to the workers
+	rows := skippedRows
+	batch := &driverStatsBatch{index: 0}
+
+readLoop:
+	for {
+		driver, err := next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return err
-		}
-
-		driverId := fmt.Sprintf("%d", record.CustId)
-		driver := firestore_structs.Driver{
-			Name:     record.Driver,
-			Location: record.Location,
-			Stats:    firestore_structs.DriverStats{},
-		}
-		driverStats := &firestore_structs.DriverStatsDetails{
-			License: record.Class,
-			IRating: record.Irating,
-		}
-
-		switch carClass {
-		case "dirt_oval":
-			driver.Stats.DirtOval = driverStats
-		case "dirt_road":
-			driver.Stats.DirtRoad = driverStats
-		case "formula_car":
-			driver.Stats.FormulaCar = driverStats
-		case "oval":
-			driver.Stats.Oval = driverStats
-		case "road":
-			driver.Stats.Road = driverStats
-		case "sports_car":
-			driver.Stats.SportsCar = driverStats
-		default:
-			return fmt.Errorf("Invalid car class: %v", carClass)
-		}
-
-		// Store in Firestore
-		_, err = firestoreClient.Collection(firestore_structs.DriversCollection).Doc(driverId).Set(firestoreContext, driver, firestore.MergeAll)
+			cancel(fmt.Errorf("error reading the drivers stats of %v: %w", checkpointName, err))
+			break
+		}
+
+		rows++
+		batch.rows = rows
+		batch.drivers = append(batch.drivers, driver)
+
+		if len(batch.drivers) < options.BatchSize {
+			continue
+		}
+
+		select {
+		case batchesChan <- batch:
+		case <-workersCtx.Done():
+			break readLoop
+		}
+		batch = &driverStatsBatch{index: batch.index + 1}
+	}
+
+	if len(batch.drivers) > 0 {
+		select {
+		case batchesChan <- batch:
+		case <-workersCtx.Done():
+		}
+	}
+	close(batchesChan) // Signal to workers that no more input will be sent
+
+	// Wait for the workers to finish
+	workersWg.Wait()
+	close(resultsChan) // Signal to the collector that no more output will be sent
+
+	// Wait for the progress to be saved
+	outputWg.Wait()
+
+	// In case of error, the next run resumes from the last checkpoint
+	if err := context.Cause(workersCtx); err != nil {
+		return err
+	}
+
+	if err := deleteCheckpoint(firestoreContext, firestoreClient, checkpointName); err != nil {
+		return fmt.Errorf("error deleting the checkpoint of %v: %w", checkpointName, err)
+	}
+
+	return nil
+}
+
+// openDriverStats downloads the stats CSV and skips the rows already written according to the checkpoint.
+// If the CSV changed since the checkpoint, all the rows are written again.
+func openDriverStats(ctx context.Context, irClient *irapi.IRacingApiClient, category irapi.Category, checkpoint *firestore_structs.DriverStatsCheckpoint) (*irapi.DriverStatsReader, int, error) {
+	driversCsv, err := irClient.GetDriverStatsByCategory(ctx, category)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if checkpoint == nil || checkpoint.Rows == 0 {
+		return driversCsv, 0, nil
+	}
+
+	var record *irapi.DriverStatsRow
+	for i := 0; i < checkpoint.Rows; i++ {
+		record, err = driversCsv.Read()
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil && record.CustId == checkpoint.CustID {
+		return driversCsv, checkpoint.Rows, nil
+	}
+
+	driversCsv.Close()
+	if err != nil && err != io.EOF {
+		return nil, 0, err
+	}
+
+	log.Printf("Drivers stats of %v changed since the checkpoint, starting from the first row", category)
+	return openDriverStats(ctx, irClient, category, nil)
+}
+
+// newDriverData returns the fields of the driver to write.
+// It's a map since MergeAll doesn't support structs: only the stats of the downloaded categories are overwritten, the others are kept.
+func newDriverData(driver *driverEntry) map[string]interface{} {
+	stats := make(map[string]interface{})
+	for _, category := range irapi.Categories {
+		if details := driver.stats.Get(category); details != nil {
+			stats[firestore_structs.DriverStatsField(category)] = details
+		}
+	}
+
+	return map[string]interface{}{
+		"name":     driver.name,
+		"location": driver.location,
+		"stats":    stats,
+	}
+}
+
+func writeDriverStatsWorker(firestoreClient *firestore.Client,
+	runAt time.Time,
+	batchesChan <-chan *driverStatsBatch,
+	resultsChan chan<- *driverStatsBatch,
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	cancel context.CancelCauseFunc,
+) {
+	defer wg.Done() // Ensure the wait group counter is decremented when the worker exits
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Another worker has already failed
+			return
+
+		case batch, ok := <-batchesChan:
+			if !ok {
+				// The input channel is closed
+				return
+			}
+
+			if err := writeDriverStatsBatch(ctx, firestoreClient, runAt, batch); err != nil {
+				cancel(err)
+				return
+			}
+
+			resultsChan <- batch
+		}
+	}
+}
+
+// writeDriverStatsBatch writes the drivers of a batch which changed since the last run, and waits for all of them to be committed.
+// The changes of the stats are added to the history of the drivers.
+func writeDriverStatsBatch(ctx context.Context, firestoreClient *firestore.Client, runAt time.Time, batch *driverStatsBatch) error {
+	collection := firestoreClient.Collection(firestore_structs.DriversCollection)
+
+	refs := make([]*firestore.DocumentRef, len(batch.drivers))
+	for i, driver := range batch.drivers {
+		refs[i] = collection.Doc(fmt.Sprintf("%d", driver.custId))
+	}
+
+	docs, err := firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		return fmt.Errorf("error reading the drivers: %w", err)
+	}
+
+	bulkWriter := firestoreClient.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0)
+	jobsCustIds := make([]int, 0)
+
+	for i, driver := range batch.drivers {
+		var stored firestore_structs.Driver
+		if docs[i].Exists() {
+			if err := docs[i].DataTo(&stored); err != nil {
+				bulkWriter.End()
+				return fmt.Errorf("error parsing driver %d: %w", driver.custId, err)
+			}
+		}
+
+		// The categories whose stats changed since the last run
+		changedCategories := make([]irapi.Category, 0)
+		for _, category := range irapi.Categories {
+			details := driver.stats.Get(category)
+			if details == nil {
+				continue
+			}
+
+			storedDetails := stored.Stats.Get(category)
+			if storedDetails == nil || storedDetails.License != details.License || storedDetails.IRating != details.IRating {
+				changedCategories = append(changedCategories, category)
+			}
+		}
+
+		if docs[i].Exists() && len(changedCategories) == 0 && stored.Name == driver.name && stored.Location == driver.location {
+			continue
+		}
+
+		job, err := bulkWriter.Set(refs[i], newDriverData(driver), firestore.MergeAll)
 		if err != nil {
-			return err
+			bulkWriter.End()
+			return fmt.Errorf("error writing driver %d: %w", driver.custId, err)
+		}
+		jobs = append(jobs, job)
+		jobsCustIds = append(jobsCustIds, driver.custId)
+
+		for _, category := range changedCategories {
+			details := driver.stats.Get(category)
+			history := refs[i].Collection(firestore_structs.DriverHistoryCollection).Doc(firestore_structs.DriverHistoryID(string(category), runAt))
+			job, err := bulkWriter.Set(history, firestore_structs.DriverStatsHistory{
+				Category: string(category),
+				License:  details.License,
+				IRating:  details.IRating,
+				Date:     runAt,
+			})
+			if err != nil {
+				bulkWriter.End()
+				return fmt.Errorf("error writing the history of driver %d: %w", driver.custId, err)
+			}
+			jobs = append(jobs, job)
+			jobsCustIds = append(jobsCustIds, driver.custId)
+		}
+
+		batch.changed++
+	}
+	bulkWriter.End()
+
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return fmt.Errorf("error writing driver %d: %w", jobsCustIds[i], err)
 		}
 	}
 
@@ -11,7 +11,10 @@ require (
 	riccardotornesello.it/sharedtelemetry/iracing/firestore v0.0.0-00010101000000-000000000000
 )
 
-replace riccardotornesello.it/sharedtelemetry/iracing/firestore => ../../../libs/iracing/firestore_go
+replace (
+	riccardotornesello.it/sharedtelemetry/iracing/firestore => ../../../libs/iracing/firestore_go
+	riccardotornesello.it/sharedtelemetry/iracing/irapi => ../../../libs/irapi
+)
 
 require (
 	cloud.google.com/go v0.116.0 // indirect
@@ -49,4 +52,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241118233622-e639e219e697 // indirect
 	google.golang.org/grpc v1.67.1 // indirect
 	google.golang.org/protobuf v1.35.2 // indirect
+	riccardotornesello.it/sharedtelemetry/iracing/irapi v0.0.0-00010101000000-000000000000 // indirect
 )
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"cloud.google.com/go/pubsub"
@@ -32,8 +33,14 @@ func main() {
 	// Get configuration
 	godotenv.Load()
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+	iRacingAuthConfig := irapi.AuthConfig{
+		Method:       os.Getenv("IRACING_AUTH_METHOD"),
+		Email:        os.Getenv("IRACING_EMAIL"),
+		Password:     os.Getenv("IRACING_PASSWORD"),
+		ClientID:     os.Getenv("IRACING_CLIENT_ID"),
+		ClientSecret: os.Getenv("IRACING_CLIENT_SECRET"),
+		TokenURL:     os.Getenv("IRACING_TOKEN_URL"),
+	}
 
 	pubSubProjectId := os.Getenv("PUBSUB_PROJECT")
 	pubSubTopicId := os.Getenv("PUBSUB_TOPIC")
@@ -53,7 +60,12 @@ func main() {
 	defer firestoreClient.Close()
 
 	// Initialize iRacing client
-	irClient, err = irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	iRacingAuth, err := irapi.NewAuthenticator(iRacingAuthConfig)
+	if err != nil {
+		log.Fatalf("irapi.NewAuthenticator: %v", err)
+	}
+
+	irClient, err = irapi.NewIRacingApiClient(firestoreContext, iRacingAuth, irapi.WithAuthHook(logAuthEvent))
 	if err != nil {
 		log.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
@@ -88,6 +100,15 @@ func main() {
 	}
 }
 
+func logAuthEvent(event irapi.AuthEvent) {
+	if event.Err != nil {
+		log.Printf("iRacing authentication %s: %v", event.Type, event.Err)
+		return
+	}
+
+	log.Printf("iRacing authentication %s", event.Type)
+}
+
 type PubSubMessage struct {
 	Message struct {
 		Data []byte `json:"data,omitempty"`
@@ -96,9 +117,14 @@ type PubSubMessage struct {
 	Subscription string `json:"subscription"`
 }
 
+// SeasonData identifies either a league season or an official series, with the range of its sessions.
 type SeasonData struct {
 	LeagueId int `json:"leagueId"`
 	SeasonId int `json:"seasonId"`
+
+	SeriesId int    `json:"seriesId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
 }
 
 func PubSubHandler(w http.ResponseWriter, r *http.Request) {
@@ -121,15 +147,20 @@ func PubSubHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if seasonData.SeriesId != 0 {
+		parseSeries(w, r, seasonData)
+		return
+	}
+
 	firstLaunchAt, err := logic.GetFirstSessionLaunchAt(seasonData.LeagueId, seasonData.SeasonId, firestoreClient, firestoreContext)
 	if err != nil {
 		handlers.ReturnException(w, err, "logic.GetFirstSessionLaunchAt")
 		return
 	}
 
-	seasonSessionsInfo, err := logic.GetLeagueSeasonSessionsInfo(seasonData.LeagueId, seasonData.SeasonId, firstLaunchAt, irClient)
+	seasonSessionsInfo, err := logic.GetLeagueSeasonSessionsInfo(r.Context(), seasonData.LeagueId, seasonData.SeasonId, firstLaunchAt, irClient)
 	if err != nil {
-		handlers.ReturnException(w, err, "logic.GetLeagueSeasonSessionsInfo")
+		handlers.ReturnError(w, err, "logic.GetLeagueSeasonSessionsInfo")
 		return
 	}
 
@@ -142,3 +173,34 @@ func PubSubHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	return
 }
+
+func parseSeries(w http.ResponseWriter, r *http.Request, seasonData SeasonData) {
+	from, err := time.Parse(time.RFC3339, seasonData.From)
+	if err != nil {
+		handlers.ReturnDiscarded(w, err, "time.Parse")
+		return
+	}
+
+	var to time.Time
+	if seasonData.To != "" {
+		to, err = time.Parse(time.RFC3339, seasonData.To)
+		if err != nil {
+			handlers.ReturnDiscarded(w, err, "time.Parse")
+			return
+		}
+	}
+
+	seriesSessionsInfo, err := logic.GetSeriesSessionsInfo(r.Context(), seasonData.SeriesId, from, to, irClient)
+	if err != nil {
+		handlers.ReturnError(w, err, "logic.GetSeriesSessionsInfo")
+		return
+	}
+
+	err = logic.SendSessionsToParse(pubSubTopic, pubSubCtx, seriesSessionsInfo)
+	if err != nil {
+		handlers.ReturnException(w, err, "logic.SendSessionsToParse")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
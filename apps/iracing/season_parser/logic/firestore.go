@@ -14,7 +14,7 @@ func GetFirstSessionLaunchAt(leagueId int, seasonId int, firestoreClient *firest
 	// It will be the oldest session in the database.
 	// If no session is found, an empty string is returned.
 
-	query := firestoreClient.Collection("iracing_sessions").
+	query := firestoreClient.Collection(firestore_structs.SessionsCollection).
 		Where("leagueId", "==", leagueId).
 		Where("seasonId", "==", seasonId).
 		OrderBy("launchAt", firestore.Asc).
@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"context"
 	"time"
 
 	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
@@ -11,9 +12,9 @@ type SessionInfo struct {
 	LaunchAt     string
 }
 
-func GetLeagueSeasonSessionsInfo(leagueId int, seasonId int, maxLaunchatStr string, irClient *irapi.IRacingApiClient) ([]SessionInfo, error) {
+func GetLeagueSeasonSessionsInfo(ctx context.Context, leagueId int, seasonId int, maxLaunchatStr string, irClient *irapi.IRacingApiClient) ([]SessionInfo, error) {
 	// Extract the sessions list (only the completed ones) for the specified series and league
-	sessions, err := irClient.GetLeagueSeasonSessions(leagueId, seasonId, true)
+	sessions, err := irClient.GetLeagueSeasonSessions(ctx, leagueId, seasonId, true)
 	if err != nil {
 		return nil, err
 	}
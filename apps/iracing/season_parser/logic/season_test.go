@@ -2,57 +2,53 @@ package logic
 
 import (
 	"context"
-	"log"
 	"os"
 	"testing"
 
-	firebase "firebase.google.com/go"
 	"github.com/joho/godotenv"
 	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
 )
 
-func TestGetLeagueSeasonSessionsInfo(t *testing.T) {
-	// Get configuration
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Error loading .env file")
-	}
+// The responses of iRacing are replayed from testdata. With IRACING_RECORD=1 the real API is called instead,
+// with the credentials of .env, and the responses are recorded into testdata.
+func newTestClient(t *testing.T) *irapi.IRacingApiClient {
+	if os.Getenv("IRACING_RECORD") == "1" {
+		godotenv.Load()
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+		iRacingEmail := os.Getenv("IRACING_EMAIL")
+		iRacingPassword := os.Getenv("IRACING_PASSWORD")
 
-	const projectID = "sharedtelemetryapp" // TODO: move to env
-
-	// Initialize database
-	firestoreContext := context.Background()
-	firebaseConf := &firebase.Config{ProjectID: projectID}
-	firebaseApp, err := firebase.NewApp(firestoreContext, firebaseConf)
-	if err != nil {
-		log.Fatalln(err)
+		irClient, err := irapi.NewIRacingApiClient(context.Background(), irapi.NewPasswordAuthenticator(iRacingEmail, iRacingPassword), irapi.WithTransport(irapitest.NewRecorder("testdata", nil)))
+		if err != nil {
+			t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+		}
+		return irClient
 	}
 
-	firestoreClient, err := firebaseApp.Firestore(firestoreContext)
+	server, err := irapitest.Load("testdata")
 	if err != nil {
-		log.Fatalln(err)
+		t.Fatalf("irapitest.Load: %v", err)
 	}
-	defer firestoreClient.Close()
+	t.Cleanup(server.Close)
 
-	// Initialize iRacing client
-	irClient, err := irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	irClient, err := irapi.NewIRacingApiClient(context.Background(), irapi.NewPasswordAuthenticator("test@example.com", "password"), irapi.WithBaseURL(server.URL))
 	if err != nil {
-		log.Fatalf("irapi.NewIRacingApiClient: %v", err)
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
+	return irClient
+}
 
-	// Test
-	firstLaunchAt, err := GetFirstSessionLaunchAt(4403, 0, firestoreClient, firestoreContext)
-	if err != nil {
-		t.Fatal(err)
-	}
+func TestGetLeagueSeasonSessionsInfo(t *testing.T) {
+	irClient := newTestClient(t)
 
-	seasonSessionsInfo, err := GetLeagueSeasonSessionsInfo(4403, 0, firstLaunchAt, irClient)
+	seasonSessionsInfo, err := GetLeagueSeasonSessionsInfo(context.Background(), 4403, 0, "2024-10-08T19:00:00Z", irClient)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	t.Logf("Season sessions info: %d", len(seasonSessionsInfo))
+	// The sessions launched after the limit are excluded
+	if len(seasonSessionsInfo) != 2 || seasonSessionsInfo[0].SubsessionId != 2001 || seasonSessionsInfo[1].SubsessionId != 2002 {
+		t.Fatalf("unexpected season sessions info: %+v", seasonSessionsInfo)
+	}
 }
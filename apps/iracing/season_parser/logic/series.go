@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// GetSeriesSessionsInfo extracts the official sessions of a series started in the given range, e.g. for the
+// qualifying challenges run on official series. A zero "to" means now.
+func GetSeriesSessionsInfo(ctx context.Context, seriesId int, from time.Time, to time.Time, irClient *irapi.IRacingApiClient) ([]SessionInfo, error) {
+	sessionsInfo := make([]SessionInfo, 0)
+	found := make(map[int]bool)
+
+	err := irClient.IterSearchSeries(ctx, irapi.SearchSeriesParams{
+		StartRangeBegin: from,
+		StartRangeEnd:   to,
+		SeriesId:        seriesId,
+		OfficialOnly:    true,
+	}, func(result irapi.SearchSeriesResult) error {
+		// The windows of the search can overlap on their bounds
+		if found[result.SubsessionId] {
+			return nil
+		}
+		found[result.SubsessionId] = true
+
+		sessionsInfo = append(sessionsInfo, SessionInfo{
+			SubsessionId: result.SubsessionId,
+			LaunchAt:     result.StartTime,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionsInfo, nil
+}
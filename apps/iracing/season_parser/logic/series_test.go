@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetSeriesSessionsInfo(t *testing.T) {
+	irClient := newTestClient(t)
+
+	tests := []struct {
+		name     string
+		seriesId int
+		from     time.Time
+		to       time.Time
+		want     []SessionInfo
+	}{
+		{
+			// The session on the bound of the two windows is returned by both
+			name:     "overlapping windows",
+			seriesId: 123,
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+			want: []SessionInfo{
+				{SubsessionId: 3001, LaunchAt: "2024-01-10T18:00:00Z"},
+				{SubsessionId: 3002, LaunchAt: "2024-02-10T18:00:00Z"},
+				{SubsessionId: 3003, LaunchAt: "2024-03-31T00:00:00Z"},
+				{SubsessionId: 3004, LaunchAt: "2024-04-05T18:00:00Z"},
+			},
+		},
+		{
+			name:     "duplicate session ids",
+			seriesId: 456,
+			from:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+			want: []SessionInfo{
+				{SubsessionId: 4001, LaunchAt: "2024-05-02T18:00:00Z"},
+				{SubsessionId: 4002, LaunchAt: "2024-05-03T18:00:00Z"},
+			},
+		},
+		{
+			name:     "no sessions",
+			seriesId: 789,
+			from:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+			want:     []SessionInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionsInfo, err := GetSeriesSessionsInfo(context.Background(), tt.seriesId, tt.from, tt.to, irClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(sessionsInfo, tt.want) {
+				t.Fatalf("unexpected series sessions info: %+v", sessionsInfo)
+			}
+		})
+	}
+}
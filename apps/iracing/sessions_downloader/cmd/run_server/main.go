@@ -14,6 +14,7 @@ import (
 	firebase "firebase.google.com/go"
 	"github.com/joho/godotenv"
 	"riccardotornesello.it/sharedtelemetry/iracing/cloudrun_utils/handlers"
+	"riccardotornesello.it/sharedtelemetry/iracing/gorm_utils/database"
 	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
 	"riccardotornesello.it/sharedtelemetry/iracing/sessions_downloader/logic"
 )
@@ -21,6 +22,8 @@ import (
 var irClient *irapi.IRacingApiClient
 var firestoreClient *firestore.Client
 var firestoreContext context.Context
+var sessionStore logic.SessionStore
+var parseSessionOptions logic.ParseSessionOptions
 
 const projectID = "sharedtelemetryapp" // TODO: move to env
 
@@ -30,25 +33,76 @@ func main() {
 	// Get configuration
 	godotenv.Load()
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+	iRacingAuthConfig := irapi.AuthConfig{
+		Method:       os.Getenv("IRACING_AUTH_METHOD"),
+		Email:        os.Getenv("IRACING_EMAIL"),
+		Password:     os.Getenv("IRACING_PASSWORD"),
+		ClientID:     os.Getenv("IRACING_CLIENT_ID"),
+		ClientSecret: os.Getenv("IRACING_CLIENT_SECRET"),
+		TokenURL:     os.Getenv("IRACING_TOKEN_URL"),
+	}
 
-	// Initialize database
-	firestoreContext = context.Background()
-	firebaseConf := &firebase.Config{ProjectID: projectID}
-	firebaseApp, err := firebase.NewApp(firestoreContext, firebaseConf)
-	if err != nil {
-		log.Fatalln(err)
+	parseSessionOptions = logic.ParseSessionOptions{
+		Workers:     10,
+		UseLapChart: os.Getenv("USE_LAP_CHART") == "true",
 	}
 
-	firestoreClient, err = firebaseApp.Firestore(firestoreContext)
+	// Comma separated, e.g. "firestore,postgres" to feed both the rankings and the CSV export
+	storeNames, err := logic.ParseStoreNames(os.Getenv("SESSION_STORES"))
 	if err != nil {
 		log.Fatalln(err)
 	}
-	defer firestoreClient.Close()
+
+	// Initialize databases
+	stores := make([]logic.SessionStore, 0, len(storeNames))
+	for _, storeName := range storeNames {
+		switch storeName {
+		case logic.StoreFirestore:
+			firestoreContext = context.Background()
+			firebaseConf := &firebase.Config{ProjectID: projectID}
+			firebaseApp, err := firebase.NewApp(firestoreContext, firebaseConf)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			firestoreClient, err = firebaseApp.Firestore(firestoreContext)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			defer firestoreClient.Close()
+
+			stores = append(stores, logic.NewFirestoreSessionStore(firestoreClient))
+
+		case logic.StorePostgres:
+			eventsDb, err := database.Connect(
+				os.Getenv("EVENTS_DB_USER"),
+				os.Getenv("EVENTS_DB_PASS"),
+				os.Getenv("EVENTS_DB_HOST"),
+				os.Getenv("EVENTS_DB_PORT"),
+				os.Getenv("EVENTS_DB_NAME"),
+				parseSessionOptions.Workers,
+				parseSessionOptions.Workers,
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			stores = append(stores, logic.NewGormSessionStore(eventsDb))
+		}
+	}
+	sessionStore = logic.NewMultiSessionStore(stores...)
 
 	// Initialize iRacing client
-	irClient, err = irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	// Fail fast on rate limits: the message is nacked instead of holding the instance asleep
+	retryPolicy := irapi.DefaultRetryPolicy
+	retryPolicy.FailOnRateLimit = true
+
+	iRacingAuth, err := irapi.NewAuthenticator(iRacingAuthConfig)
+	if err != nil {
+		log.Fatalf("irapi.NewAuthenticator: %v", err)
+	}
+
+	irClient, err = irapi.NewIRacingApiClient(context.Background(), iRacingAuth, irapi.WithRetryPolicy(retryPolicy), irapi.WithAuthHook(logAuthEvent))
 	if err != nil {
 		log.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
@@ -73,6 +127,15 @@ func main() {
 	}
 }
 
+func logAuthEvent(event irapi.AuthEvent) {
+	if event.Err != nil {
+		log.Printf("iRacing authentication %s: %v", event.Type, event.Err)
+		return
+	}
+
+	log.Printf("iRacing authentication %s", event.Type)
+}
+
 type PubSubMessage struct {
 	Message struct {
 		Data []byte `json:"data,omitempty"`
@@ -112,8 +175,8 @@ func PubSubHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := logic.ParseSession(irClient, sessionData.SubsessionId, launchAt, firestoreClient, firestoreContext, 10); err != nil {
-		handlers.ReturnException(w, err, "logic.ParseSession")
+	if err := logic.ParseSession(r.Context(), irClient, sessionData.SubsessionId, launchAt, sessionStore, parseSessionOptions); err != nil {
+		handlers.ReturnError(w, err, "logic.ParseSession")
 		return
 	}
 
@@ -7,101 +7,123 @@ import (
 	"sync"
 	"time"
 
-	"cloud.google.com/go/firestore"
-	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
 	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
 )
 
+// Options of ParseSession
+type ParseSessionOptions struct {
+	// Parallel calls for the per-driver lap data
+	Workers int
+
+	// Get the laps of the race simsessions from the lap chart, with a single call instead of one per driver
+	UseLapChart bool
+}
+
 type workerResponse struct {
 	simsessionNumber int
-	custId           int
-	laps             []*firestore_structs.Lap
+	entryId          int // The driver, or the team in team events
+	laps             []*Lap
 }
 
-func ParseSession(irClient *irapi.IRacingApiClient, subsessionId int, subsessionLaunchAt time.Time, firestoreClient *firestore.Client, firestoreContext context.Context, workers int) error {
-	db := firestoreClient.Collection("iracing_sessions")
-
+func ParseSession(ctx context.Context, irClient *irapi.IRacingApiClient, subsessionId int, subsessionLaunchAt time.Time, store SessionStore, options ParseSessionOptions) error {
 	// Skip if already in the database
-	dbSession := firestore_structs.Session{}
-	dbSessionDoc := db.Doc(fmt.Sprintf("%d", subsessionId))
-	dbSessionSnap, err := dbSessionDoc.Get(firestoreContext)
+	parsed, err := store.IsParsed(ctx, subsessionId)
 	if err != nil {
-		// TODO: handle
-	} else {
-		err = dbSessionSnap.DataTo(&dbSession)
-		if err != nil {
-			return fmt.Errorf("error parsing session %d from the database: %w", subsessionId, err)
-		}
+		return fmt.Errorf("error getting session %d from the database: %w", subsessionId, err)
 	}
 
-	if dbSession.Parsed {
+	if parsed {
 		log.Printf("Session %d already parsed", subsessionId)
 		return nil
 	}
 
 	// Get the whole session results to extract simsessions and participants
-	results, err := irClient.GetResults(subsessionId)
+	results, err := irClient.GetResults(ctx, subsessionId)
 	if err != nil {
 		return fmt.Errorf("error getting results for session %d: %w", subsessionId, err)
 	}
 
-	session := firestore_structs.Session{
-		Parsed: true,
-
-		LeagueID: results.LeagueId,
-		SeasonID: results.SeasonId,
-		LaunchAt: subsessionLaunchAt, // TODO: populate in league parser
-		TrackID:  results.Track.TrackId,
+	// TODO: populate launchAt in league parser
+	if err := store.SaveResults(ctx, results, subsessionLaunchAt); err != nil {
+		return fmt.Errorf("error saving the results of session %d: %w", subsessionId, err)
+	}
 
-		Simsessions: make([]*firestore_structs.SessionSimsession, len(results.SessionResults)),
+	// The laps saved by the previous attempts, so only the missing participants are downloaded
+	parsedParticipants, err := store.ParsedParticipants(ctx, results)
+	if err != nil {
+		return fmt.Errorf("error getting the parsed participants of session %d: %w", subsessionId, err)
+	}
+	if len(parsedParticipants) > 0 {
+		log.Printf("Resuming session %d with %d participants already parsed", subsessionId, len(parsedParticipants))
 	}
 
 	// For each simsession, get the results for each driver.
 	// results.SessionResults: one for each simsession (practice, quali...)
 	// results.SessionResults[i].Results: one for each driver
 
-	// Count the number of tasks to be done (one for each driver in each simsession)
+	// Count the number of tasks to be done (one for each missing driver in each simsession)
 	tasksCount := 0
 	for _, simSessionResult := range results.SessionResults {
-		tasksCount += len(simSessionResult.Results)
+		if useLapChart(options, simSessionResult.SimsessionType) {
+			continue
+		}
+		for _, participant := range simSessionResult.Results {
+			if !parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, participant.EntryId())] {
+				tasksCount++
+			}
+		}
 	}
 
 	tasksChan := make(chan sessionLapTask, tasksCount)
-	resultsChan := make(chan *workerResponse, 0)
-	ctx, cancel := context.WithCancelCause(context.Background())
+	resultsChan := make(chan ParticipantKey, 0)
+	workersCtx, cancel := context.WithCancelCause(ctx)
 	defer cancel(nil)
 
+	// Don't start more workers than the calls left until the rate limit resets
+	workers := options.Workers
+	if budget := irClient.RateLimitBudget(); budget.Known() && budget.Remaining < workers {
+		workers = max(budget.Remaining, 1)
+	}
+
 	// Start the workers to call the API and generate the lap models
 	var workersWg sync.WaitGroup
 	for i := 0; i < workers; i++ {
 		workersWg.Add(1)
 		go parseSessionLapsWorker(irClient,
+			store,
 			tasksChan,
 			resultsChan,
-			ctx,
+			workersCtx,
 			&workersWg,
 			cancel,
 		)
 	}
 
-	// Collect the laps in background
-	lapResults := make([]*workerResponse, 0)
+	// Collect the parsed participants in background
 	var outputWg sync.WaitGroup
 	outputWg.Add(1)
 	go func() {
 		defer outputWg.Done()
-		for result := range resultsChan {
-			lapResults = append(lapResults, result)
+		for key := range resultsChan {
+			parsedParticipants[key] = true
 		}
 	}()
 
 	// Send the tasks to the workers
 	for _, simSessionResult := range results.SessionResults {
+		if useLapChart(options, simSessionResult.SimsessionType) {
+			continue
+		}
+
 		for _, participant := range simSessionResult.Results {
+			if parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, participant.EntryId())] {
+				continue
+			}
+
 			tasksChan <- sessionLapTask{
 				subsessionId:     results.SubsessionId,
 				simsessionNumber: simSessionResult.SimsessionNumber,
-				custId:           participant.CustId,
+				result:           participant,
 			}
 		}
 	}
@@ -114,49 +136,52 @@ func ParseSession(irClient *irapi.IRacingApiClient, subsessionId int, subsession
 	// Wait for the outputs collection to finish
 	outputWg.Wait()
 
-	// In case of error in the workers, return it
-	if err = context.Cause(ctx); err != nil {
+	// In case of error in the workers, return it. The laps downloaded so far are already saved.
+	if err = context.Cause(workersCtx); err != nil {
 		return err
 	}
 
-	// Create the simsessions and participants maps
-	simsessions := make(map[int]*firestore_structs.SessionSimsession)
-	for i, result := range results.SessionResults {
-		simsession := firestore_structs.SessionSimsession{
-			SimsessionNumber: result.SimsessionNumber,
-			SimsessionType:   result.SimsessionType,
-			SimsessionName:   result.SimsessionName,
-
-			Participants: make([]*firestore_structs.SessionSimsessionParticipant, len(result.Results)),
+	// Get the laps of all the drivers of the races at once
+	for _, simSessionResult := range results.SessionResults {
+		if !useLapChart(options, simSessionResult.SimsessionType) {
+			continue
 		}
 
-		simsessions[result.SimsessionNumber] = &simsession
-		session.Simsessions[i] = &simsession
+		err := parseLapChart(ctx, irClient, store, subsessionId, simSessionResult, parsedParticipants)
+		if err != nil {
+			return err
+		}
 	}
 
-	simsessionParticipants := make(map[int]map[int]*firestore_structs.SessionSimsessionParticipant)
-	for _, result := range results.SessionResults {
-		simsessionParticipants[result.SimsessionNumber] = make(map[int]*firestore_structs.SessionSimsessionParticipant)
+	// Save the events of the simsessions, except the ones saved by a previous attempt
+	parsedEvents, err := store.ParsedEvents(ctx, results)
+	if err != nil {
+		return fmt.Errorf("error getting the parsed events of session %d: %w", subsessionId, err)
+	}
 
-		for i, participantResults := range result.Results {
-			participant := firestore_structs.SessionSimsessionParticipant{
-				CustID: participantResults.CustId,
-				CarID:  participantResults.CarId,
+	for _, simSessionResult := range results.SessionResults {
+		for _, participant := range simSessionResult.Results {
+			if !parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, participant.EntryId())] {
+				return fmt.Errorf("missing laps for session %d, simsession %d, entry %d", subsessionId, simSessionResult.SimsessionNumber, participant.EntryId())
 			}
+		}
 
-			simsessionParticipants[result.SimsessionNumber][participantResults.CustId] = &participant
-			simsessions[result.SimsessionNumber].Participants[i] = &participant
+		if parsedEvents[simSessionResult.SimsessionNumber] {
+			continue
 		}
-	}
 
-	// Populate the laps in the participants
-	for _, lapResult := range lapResults {
-		simsessionParticipants[lapResult.simsessionNumber][lapResult.custId].Laps = lapResult.laps
+		events, err := getSimsessionEvents(ctx, irClient, subsessionId, simSessionResult.SimsessionNumber)
+		if err != nil {
+			return err
+		}
+
+		if err := store.SaveEvents(ctx, subsessionId, simSessionResult.SimsessionNumber, events); err != nil {
+			return fmt.Errorf("error saving the events of session %d, simsession %d: %w", subsessionId, simSessionResult.SimsessionNumber, err)
+		}
 	}
 
-	// Save the session in the database
-	_, err = db.Doc(fmt.Sprintf("%d", subsessionId)).Set(firestoreContext, session)
-	if err != nil {
+	// Mark the session as parsed only now that every participant is present
+	if err := store.SetParsed(ctx, results, subsessionLaunchAt); err != nil {
 		return fmt.Errorf("error updating session %d in the database: %w", subsessionId, err)
 	}
 
@@ -166,12 +191,13 @@ func ParseSession(irClient *irapi.IRacingApiClient, subsessionId int, subsession
 type sessionLapTask struct {
 	subsessionId     int
 	simsessionNumber int
-	custId           int
+	result           irapi.DriverResult
 }
 
 func parseSessionLapsWorker(irClient *irapi.IRacingApiClient,
+	store SessionStore,
 	tasksChan <-chan sessionLapTask,
-	resultsChan chan<- *workerResponse,
+	resultsChan chan<- ParticipantKey,
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	cancel context.CancelCauseFunc,
@@ -190,27 +216,111 @@ func parseSessionLapsWorker(irClient *irapi.IRacingApiClient,
 				return
 			}
 
-			res, err := irClient.GetResultsLapData(task.subsessionId, task.simsessionNumber, task.custId)
+			laps := make([]*Lap, 0)
+			_, err := irClient.IterResultsLapDataByEntry(ctx, task.subsessionId, task.simsessionNumber, task.result, func(lap irapi.ResultsLapDataChunk) error {
+				laps = append(laps, newLap(lap))
+				return nil
+			})
 			if err != nil {
-				cancel(fmt.Errorf("error getting lap data for session %d, simsession %d, cust %d: %w", task.subsessionId, task.simsessionNumber, task.custId, err))
+				cancel(fmt.Errorf("error getting lap data for session %d, simsession %d, entry %d: %w", task.subsessionId, task.simsessionNumber, task.result.EntryId(), err))
 				return
 			}
 
-			laps := make([]*firestore_structs.Lap, len(res.Laps))
-			for i, lap := range res.Laps {
-				laps[i] = &firestore_structs.Lap{
-					LapEvents: lap.LapEvents,
-					Incident:  lap.Incident,
-					LapTime:   lap.LapTime,
-					LapNumber: lap.LapNumber,
-				}
+			// Once saved, a retry doesn't download the participant again
+			if err := store.SaveParticipantLaps(ctx, task.subsessionId, task.simsessionNumber, task.result, laps); err != nil {
+				cancel(fmt.Errorf("error saving the laps of session %d, simsession %d, entry %d: %w", task.subsessionId, task.simsessionNumber, task.result.EntryId(), err))
+				return
 			}
 
-			resultsChan <- &workerResponse{
-				simsessionNumber: task.simsessionNumber,
-				custId:           task.custId,
-				laps:             laps,
+			resultsChan <- NewParticipantKey(task.simsessionNumber, task.result.EntryId())
+		}
+	}
+}
+
+// getSimsessionEvents downloads the race control timeline, so the stewards can review the penalties.
+func getSimsessionEvents(ctx context.Context, irClient *irapi.IRacingApiClient, subsessionId int, simsessionNumber int) ([]irapi.ResultsEventLogEntry, error) {
+	events := make([]irapi.ResultsEventLogEntry, 0)
+
+	_, err := irClient.IterResultsEventLog(ctx, subsessionId, simsessionNumber, func(event irapi.ResultsEventLogEntry) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting event log for session %d, simsession %d: %w", subsessionId, simsessionNumber, err)
+	}
+
+	return events, nil
+}
+
+func useLapChart(options ParseSessionOptions, simsessionType irapi.SimsessionType) bool {
+	return options.UseLapChart && simsessionType.IsRace()
+}
+
+// parseLapChart downloads the lap chart of a simsession if some of its participants are missing,
+// and saves the laps of every participant.
+func parseLapChart(ctx context.Context, irClient *irapi.IRacingApiClient, store SessionStore, subsessionId int, simSessionResult irapi.SessionResult, parsedParticipants map[ParticipantKey]bool) error {
+	missing := false
+	for _, participant := range simSessionResult.Results {
+		if !parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, participant.EntryId())] {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	lapResults, err := getLapChartLaps(ctx, irClient, subsessionId, simSessionResult.SimsessionNumber)
+	if err != nil {
+		return err
+	}
+
+	entriesLaps := make(map[int][]*Lap, len(lapResults))
+	for _, lapResult := range lapResults {
+		entriesLaps[lapResult.entryId] = lapResult.laps
+	}
+
+	// The lap chart can include drivers without results, e.g. spectators of team events, and miss the participants without laps
+	for _, participant := range simSessionResult.Results {
+		err := store.SaveParticipantLaps(ctx, subsessionId, simSessionResult.SimsessionNumber, participant, entriesLaps[participant.EntryId()])
+		if err != nil {
+			return fmt.Errorf("error saving the laps of session %d, simsession %d, entry %d: %w", subsessionId, simSessionResult.SimsessionNumber, participant.EntryId(), err)
+		}
+		parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, participant.EntryId())] = true
+	}
+
+	return nil
+}
+
+// getLapChartLaps splits the lap chart of a simsession by car, i.e. by driver or by team.
+func getLapChartLaps(ctx context.Context, irClient *irapi.IRacingApiClient, subsessionId int, simsessionNumber int) ([]*workerResponse, error) {
+	driversLaps := make(map[int]*workerResponse)
+	results := make([]*workerResponse, 0)
+
+	_, err := irClient.IterResultsLapChartData(ctx, subsessionId, simsessionNumber, func(lap irapi.ResultsLapChartEntry) error {
+		// The group is the team in team events, else the driver
+		entryId := lap.GroupId
+		if entryId == 0 {
+			entryId = lap.CustId
+		}
+
+		driverLaps, ok := driversLaps[entryId]
+		if !ok {
+			driverLaps = &workerResponse{
+				simsessionNumber: simsessionNumber,
+				entryId:          entryId,
+				laps:             make([]*Lap, 0),
 			}
+			driversLaps[entryId] = driverLaps
+			results = append(results, driverLaps)
 		}
+
+		driverLaps.laps = append(driverLaps.laps, newLapFromLapChart(lap))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting lap chart for session %d, simsession %d: %w", subsessionId, simsessionNumber, err)
 	}
+
+	return results, nil
 }
@@ -36,13 +36,13 @@ func TestParseSession(t *testing.T) {
 	defer firestoreClient.Close()
 
 	// Initialize iRacing client
-	irClient, err := irapi.NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	irClient, err := irapi.NewIRacingApiClient(firestoreContext, irapi.NewPasswordAuthenticator(iRacingEmail, iRacingPassword))
 	if err != nil {
 		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
 
 	// Get the sessions
-	sessions, err := irClient.GetLeagueSeasonSessions(4403, 0, true)
+	sessions, err := irClient.GetLeagueSeasonSessions(firestoreContext, 4403, 0, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -56,7 +56,7 @@ func TestParseSession(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		err = ParseSession(irClient, sessions.Sessions[i].SubsessionId, launchAt, firestoreClient, firestoreContext, 10)
+		err = ParseSession(firestoreContext, irClient, sessions.Sessions[i].SubsessionId, launchAt, NewFirestoreSessionStore(firestoreClient), ParseSessionOptions{Workers: 10})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,219 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// SessionStore is the database where ParseSession saves the sessions.
+// The laps are saved one participant at a time, so an interrupted download resumes from the missing ones.
+type SessionStore interface {
+	// IsParsed reports whether the session is already complete in the store
+	IsParsed(ctx context.Context, subsessionId int) (bool, error)
+
+	// SaveResults saves the session with its simsessions and participants, before their laps.
+	// The stores whose readers don't check the parsed flag can wait for SetParsed instead.
+	SaveResults(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error
+
+	// ParsedParticipants lists the participants whose laps are already saved
+	ParsedParticipants(ctx context.Context, results *irapi.ResultsResponse) (map[ParticipantKey]bool, error)
+
+	SaveParticipantLaps(ctx context.Context, subsessionId int, simsessionNumber int, participant irapi.DriverResult, laps []*Lap) error
+
+	// ParsedEvents lists the simsessions whose events are already saved, by number
+	ParsedEvents(ctx context.Context, results *irapi.ResultsResponse) (map[int]bool, error)
+
+	SaveEvents(ctx context.Context, subsessionId int, simsessionNumber int, events []irapi.ResultsEventLogEntry) error
+
+	// SetParsed marks the session as complete, once every participant has its laps
+	SetParsed(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error
+}
+
+// ParticipantKey identifies a participant of a simsession, by driver or by team
+type ParticipantKey struct {
+	SimsessionNumber int
+	EntryId          int
+}
+
+func NewParticipantKey(simsessionNumber int, entryId int) ParticipantKey {
+	return ParticipantKey{SimsessionNumber: simsessionNumber, EntryId: entryId}
+}
+
+// Lap is a lap of a participant, from the lap data or from the lap chart
+type Lap struct {
+	CustId      int // The driver of the lap, also in team events
+	LapNumber   int
+	LapTime     int
+	Incident    bool
+	LapEvents   []string
+	LapPosition int // Only in the lap chart
+}
+
+func newLap(lap irapi.ResultsLapDataChunk) *Lap {
+	return &Lap{
+		CustId:    lap.CustId,
+		LapNumber: lap.LapNumber,
+		LapTime:   lap.LapTime,
+		Incident:  lap.Incident,
+		LapEvents: lap.LapEvents,
+	}
+}
+
+func newLapFromLapChart(lap irapi.ResultsLapChartEntry) *Lap {
+	return &Lap{
+		CustId:      lap.CustId,
+		LapNumber:   lap.LapNumber,
+		LapTime:     lap.LapTime,
+		Incident:    lap.Incident,
+		LapEvents:   lap.LapEvents,
+		LapPosition: lap.LapPosition,
+	}
+}
+
+// Names of the stores in the configuration
+const (
+	StoreFirestore = "firestore"
+	StorePostgres  = "postgres"
+)
+
+// ParseStoreNames parses a comma separated list of stores, e.g. "firestore,postgres".
+// An empty list means Firestore only.
+func ParseStoreNames(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return []string{StoreFirestore}, nil
+	}
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case StoreFirestore, StorePostgres:
+			names = append(names, name)
+		default:
+			return nil, fmt.Errorf("invalid session store: %v", name)
+		}
+	}
+
+	return names, nil
+}
+
+// multiSessionStore saves the sessions in several stores, e.g. Firestore for the rankings and Postgres for the CSV.
+// A session is parsed only when it's parsed in all of them.
+type multiSessionStore struct {
+	stores []SessionStore
+}
+
+func NewMultiSessionStore(stores ...SessionStore) SessionStore {
+	if len(stores) == 1 {
+		return stores[0]
+	}
+	return &multiSessionStore{stores: stores}
+}
+
+func (m *multiSessionStore) IsParsed(ctx context.Context, subsessionId int) (bool, error) {
+	for _, store := range m.stores {
+		parsed, err := store.IsParsed(ctx, subsessionId)
+		if err != nil || !parsed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (m *multiSessionStore) SaveResults(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	for _, store := range m.stores {
+		if err := store.SaveResults(ctx, results, launchAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParsedParticipants returns the participants parsed in all the stores, the others are saved again everywhere
+func (m *multiSessionStore) ParsedParticipants(ctx context.Context, results *irapi.ResultsResponse) (map[ParticipantKey]bool, error) {
+	var parsedParticipants map[ParticipantKey]bool
+
+	for _, store := range m.stores {
+		storeParticipants, err := store.ParsedParticipants(ctx, results)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsedParticipants == nil {
+			parsedParticipants = storeParticipants
+			continue
+		}
+
+		for key := range parsedParticipants {
+			if !storeParticipants[key] {
+				delete(parsedParticipants, key)
+			}
+		}
+	}
+
+	if parsedParticipants == nil {
+		parsedParticipants = make(map[ParticipantKey]bool)
+	}
+
+	return parsedParticipants, nil
+}
+
+func (m *multiSessionStore) SaveParticipantLaps(ctx context.Context, subsessionId int, simsessionNumber int, participant irapi.DriverResult, laps []*Lap) error {
+	for _, store := range m.stores {
+		if err := store.SaveParticipantLaps(ctx, subsessionId, simsessionNumber, participant, laps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParsedEvents returns the simsessions parsed in all the stores, the others are saved again everywhere
+func (m *multiSessionStore) ParsedEvents(ctx context.Context, results *irapi.ResultsResponse) (map[int]bool, error) {
+	var parsedEvents map[int]bool
+
+	for _, store := range m.stores {
+		storeEvents, err := store.ParsedEvents(ctx, results)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsedEvents == nil {
+			parsedEvents = storeEvents
+			continue
+		}
+
+		for simsessionNumber := range parsedEvents {
+			if !storeEvents[simsessionNumber] {
+				delete(parsedEvents, simsessionNumber)
+			}
+		}
+	}
+
+	if parsedEvents == nil {
+		parsedEvents = make(map[int]bool)
+	}
+
+	return parsedEvents, nil
+}
+
+func (m *multiSessionStore) SaveEvents(ctx context.Context, subsessionId int, simsessionNumber int, events []irapi.ResultsEventLogEntry) error {
+	for _, store := range m.stores {
+		if err := store.SaveEvents(ctx, subsessionId, simsessionNumber, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiSessionStore) SetParsed(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	for _, store := range m.stores {
+		if err := store.SetParsed(ctx, results, launchAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// FirestoreSessionStore saves the sessions in Firestore, read by the rankings.
+// The laps and the events are in the subcollections of the session document.
+type FirestoreSessionStore struct {
+	client *firestore.Client
+}
+
+func NewFirestoreSessionStore(client *firestore.Client) *FirestoreSessionStore {
+	return &FirestoreSessionStore{client: client}
+}
+
+func (s *FirestoreSessionStore) sessionDoc(subsessionId int) *firestore.DocumentRef {
+	return s.client.Collection(firestore_structs.SessionsCollection).Doc(strconv.Itoa(subsessionId))
+}
+
+func (s *FirestoreSessionStore) simsessionDoc(subsessionId int, simsessionNumber int) *firestore.DocumentRef {
+	return s.sessionDoc(subsessionId).
+		Collection(firestore_structs.SessionSimsessionsCollection).
+		Doc(firestore_structs.SessionSimsessionID(simsessionNumber))
+}
+
+func (s *FirestoreSessionStore) participantsCollection(subsessionId int, simsessionNumber int) *firestore.CollectionRef {
+	return s.simsessionDoc(subsessionId, simsessionNumber).Collection(firestore_structs.SessionParticipantsCollection)
+}
+
+func (s *FirestoreSessionStore) IsParsed(ctx context.Context, subsessionId int) (bool, error) {
+	dbSessionSnap, err := s.sessionDoc(subsessionId).Get(ctx)
+	if err != nil {
+		// TODO: handle
+		return false, nil
+	}
+
+	dbSession := firestore_structs.Session{}
+	if err := dbSessionSnap.DataTo(&dbSession); err != nil {
+		return false, fmt.Errorf("error parsing session %d from the database: %w", subsessionId, err)
+	}
+
+	return dbSession.Parsed, nil
+}
+
+// SaveResults doesn't write anything: the session document is saved by SetParsed, so the rankings don't read sessions without laps
+func (s *FirestoreSessionStore) SaveResults(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	return nil
+}
+
+// ParsedParticipants lists the participant documents, without reading the laps
+func (s *FirestoreSessionStore) ParsedParticipants(ctx context.Context, results *irapi.ResultsResponse) (map[ParticipantKey]bool, error) {
+	parsedParticipants := make(map[ParticipantKey]bool)
+
+	for _, simSessionResult := range results.SessionResults {
+		refs, err := s.participantsCollection(results.SubsessionId, simSessionResult.SimsessionNumber).DocumentRefs(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ref := range refs {
+			entryId, err := strconv.Atoi(ref.ID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid participant %s: %w", ref.Path, err)
+			}
+			parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, entryId)] = true
+		}
+	}
+
+	return parsedParticipants, nil
+}
+
+func (s *FirestoreSessionStore) SaveParticipantLaps(ctx context.Context, subsessionId int, simsessionNumber int, participant irapi.DriverResult, laps []*Lap) error {
+	dbLaps := make([]*firestore_structs.Lap, len(laps))
+	for i, lap := range laps {
+		dbLaps[i] = &firestore_structs.Lap{
+			CustID:      lap.CustId,
+			LapEvents:   lap.LapEvents,
+			Incident:    lap.Incident,
+			LapTime:     lap.LapTime,
+			LapNumber:   lap.LapNumber,
+			LapPosition: lap.LapPosition,
+		}
+	}
+
+	_, err := s.participantsCollection(subsessionId, simsessionNumber).
+		Doc(firestore_structs.SessionParticipantID(participant.EntryId())).
+		Set(ctx, firestore_structs.NewSessionParticipantLaps(simsessionNumber, participant, dbLaps))
+	return err
+}
+
+// ParsedEvents reads the simsession documents, which are written with their events.
+// The missing ones can still be listed, since the participants subcollection is under them.
+func (s *FirestoreSessionStore) ParsedEvents(ctx context.Context, results *irapi.ResultsResponse) (map[int]bool, error) {
+	refs := make([]*firestore.DocumentRef, len(results.SessionResults))
+	for i, simSessionResult := range results.SessionResults {
+		refs[i] = s.simsessionDoc(results.SubsessionId, simSessionResult.SimsessionNumber)
+	}
+
+	docs, err := s.client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedEvents := make(map[int]bool, len(docs))
+	for i, doc := range docs {
+		if doc.Exists() {
+			parsedEvents[results.SessionResults[i].SimsessionNumber] = true
+		}
+	}
+
+	return parsedEvents, nil
+}
+
+func (s *FirestoreSessionStore) SaveEvents(ctx context.Context, subsessionId int, simsessionNumber int, events []irapi.ResultsEventLogEntry) error {
+	simsession := firestore_structs.SessionSimsessionDetails{
+		SimsessionNumber: simsessionNumber,
+		Events:           make([]*firestore_structs.SessionEvent, len(events)),
+	}
+	for i, event := range events {
+		simsession.Events[i] = firestore_structs.NewSessionEvent(event)
+	}
+
+	_, err := s.simsessionDoc(subsessionId, simsessionNumber).Set(ctx, simsession)
+	return err
+}
+
+func (s *FirestoreSessionStore) SetParsed(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	session := firestore_structs.NewSession(results, launchAt)
+	session.Parsed = true
+
+	_, err := s.sessionDoc(results.SubsessionId).Set(ctx, session)
+	return err
+}
@@ -0,0 +1,165 @@
+package logic
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	firestore_structs "riccardotornesello.it/sharedtelemetry/iracing/firestore"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// fakeFirestoreServer keeps the documents in memory, with only the calls used by the store
+type fakeFirestoreServer struct {
+	firestorepb.UnimplementedFirestoreServer
+
+	mutex sync.Mutex
+	docs  map[string]*firestorepb.Document
+}
+
+func (f *fakeFirestoreServer) Commit(ctx context.Context, req *firestorepb.CommitRequest) (*firestorepb.CommitResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	response := &firestorepb.CommitResponse{CommitTime: timestamppb.Now()}
+	for _, write := range req.Writes {
+		if doc := write.GetUpdate(); doc != nil {
+			doc.CreateTime = response.CommitTime
+			if stored, ok := f.docs[doc.Name]; ok {
+				doc.CreateTime = stored.CreateTime
+			}
+			doc.UpdateTime = response.CommitTime
+			f.docs[doc.Name] = doc
+		}
+		if name := write.GetDelete(); name != "" {
+			delete(f.docs, name)
+		}
+		response.WriteResults = append(response.WriteResults, &firestorepb.WriteResult{UpdateTime: response.CommitTime})
+	}
+
+	return response, nil
+}
+
+func (f *fakeFirestoreServer) BatchGetDocuments(req *firestorepb.BatchGetDocumentsRequest, stream firestorepb.Firestore_BatchGetDocumentsServer) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, name := range req.Documents {
+		response := &firestorepb.BatchGetDocumentsResponse{ReadTime: timestamppb.Now()}
+		if doc, ok := f.docs[name]; ok {
+			response.Result = &firestorepb.BatchGetDocumentsResponse_Found{Found: doc}
+		} else {
+			response.Result = &firestorepb.BatchGetDocumentsResponse_Missing{Missing: name}
+		}
+
+		if err := stream.Send(response); err != nil && err != io.EOF {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDocuments also returns the missing documents with subcollections, like Firestore with ShowMissing
+func (f *fakeFirestoreServer) ListDocuments(ctx context.Context, req *firestorepb.ListDocumentsRequest) (*firestorepb.ListDocumentsResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	prefix := req.Parent + "/" + req.CollectionId + "/"
+	found := make(map[string]bool)
+	response := &firestorepb.ListDocumentsResponse{}
+	for name := range f.docs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		id, descendant, _ := strings.Cut(strings.TrimPrefix(name, prefix), "/")
+		if (descendant != "" && !req.ShowMissing) || found[id] {
+			continue
+		}
+		found[id] = true
+		response.Documents = append(response.Documents, &firestorepb.Document{Name: prefix + id})
+	}
+
+	return response, nil
+}
+
+func newFakeFirestoreClient(t *testing.T) *firestore.Client {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	firestorepb.RegisterFirestoreServer(server, &fakeFirestoreServer{docs: make(map[string]*firestorepb.Document)})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	t.Setenv("FIRESTORE_EMULATOR_HOST", listener.Addr().String())
+	client, err := firestore.NewClient(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestFirestoreSessionStoreEvents(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeFirestoreClient(t)
+	store := NewFirestoreSessionStore(client)
+
+	participant := irapi.DriverResult{CustId: 10, CarId: 1}
+	results := &irapi.ResultsResponse{
+		SubsessionId: 1,
+		SessionResults: []irapi.SessionResult{
+			{SimsessionNumber: 0, Results: []irapi.DriverResult{participant}},
+		},
+	}
+
+	// The laps are saved before the events, under the simsession document
+	if err := store.SaveParticipantLaps(ctx, 1, 0, participant, []*Lap{{CustId: 10, LapNumber: 1}}); err != nil {
+		t.Fatalf("store.SaveParticipantLaps: %v", err)
+	}
+
+	parsedEvents, err := store.ParsedEvents(ctx, results)
+	if err != nil {
+		t.Fatalf("store.ParsedEvents: %v", err)
+	}
+	if len(parsedEvents) != 0 {
+		t.Fatalf("expected the events not parsed, got %v", parsedEvents)
+	}
+
+	events := []irapi.ResultsEventLogEntry{{EventSeq: 1, CustId: 10, Description: "Penalty"}}
+	if err := store.SaveEvents(ctx, 1, 0, events); err != nil {
+		t.Fatalf("store.SaveEvents: %v", err)
+	}
+
+	parsedEvents, err = store.ParsedEvents(ctx, results)
+	if err != nil {
+		t.Fatalf("store.ParsedEvents: %v", err)
+	}
+	if len(parsedEvents) != 1 || !parsedEvents[0] {
+		t.Fatalf("expected the events parsed, got %v", parsedEvents)
+	}
+
+	snap, err := store.simsessionDoc(1, 0).Get(ctx)
+	if err != nil {
+		t.Fatalf("simsession.Get: %v", err)
+	}
+	var simsession firestore_structs.SessionSimsessionDetails
+	if err := snap.DataTo(&simsession); err != nil {
+		t.Fatal(err)
+	}
+	if len(simsession.Events) != 1 || simsession.Events[0].Description != "Penalty" {
+		t.Fatalf("unexpected events: %+v", simsession.Events)
+	}
+}
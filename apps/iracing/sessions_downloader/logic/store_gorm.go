@@ -0,0 +1,196 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"riccardotornesello.it/sharedtelemetry/iracing/events_models"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// GormSessionStore saves the sessions in the events tables, read by the CSV export.
+// In team events there is a participant for each driver of the team, since the laps are by driver.
+type GormSessionStore struct {
+	db *gorm.DB
+}
+
+func NewGormSessionStore(db *gorm.DB) *GormSessionStore {
+	return &GormSessionStore{db: db}
+}
+
+func (s *GormSessionStore) IsParsed(ctx context.Context, subsessionId int) (bool, error) {
+	var session events_models.Session
+	err := s.db.WithContext(ctx).
+		Select("parsed").
+		Where("subsession_id = ?", subsessionId).
+		Take(&session).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return session.Parsed, nil
+}
+
+// SaveResults creates the rows the laps refer to. The rows saved by a previous attempt are kept, with their progress.
+func (s *GormSessionStore) SaveResults(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(events_models.NewSession(results, launchAt)).
+			Error
+		if err != nil {
+			return err
+		}
+
+		for _, simSessionResult := range results.SessionResults {
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(events_models.NewSessionSimsession(results.SubsessionId, simSessionResult)).
+				Error
+			if err != nil {
+				return err
+			}
+
+			participants := make([]*events_models.SessionSimsessionParticipant, 0, len(simSessionResult.Results))
+			for _, result := range simSessionResult.Results {
+				for _, driver := range participantDrivers(result) {
+					participants = append(participants, events_models.NewSessionSimsessionParticipant(results.SubsessionId, simSessionResult.SimsessionNumber, driver))
+				}
+			}
+			if len(participants) == 0 {
+				continue
+			}
+
+			err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(participants).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ParsedParticipants returns the entries whose drivers all have their laps
+func (s *GormSessionStore) ParsedParticipants(ctx context.Context, results *irapi.ResultsResponse) (map[ParticipantKey]bool, error) {
+	var rows []*events_models.SessionSimsessionParticipant
+	err := s.db.WithContext(ctx).
+		Select("simsession_number", "cust_id").
+		Where("subsession_id = ? AND laps_parsed", results.SubsessionId).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	parsedDrivers := make(map[ParticipantKey]bool, len(rows))
+	for _, row := range rows {
+		parsedDrivers[NewParticipantKey(row.SimsessionNumber, row.CustID)] = true
+	}
+
+	parsedParticipants := make(map[ParticipantKey]bool)
+	for _, simSessionResult := range results.SessionResults {
+		for _, result := range simSessionResult.Results {
+			parsed := true
+			for _, driver := range participantDrivers(result) {
+				if !parsedDrivers[NewParticipantKey(simSessionResult.SimsessionNumber, driver.CustId)] {
+					parsed = false
+					break
+				}
+			}
+
+			if parsed {
+				parsedParticipants[NewParticipantKey(simSessionResult.SimsessionNumber, result.EntryId())] = true
+			}
+		}
+	}
+
+	return parsedParticipants, nil
+}
+
+// SaveParticipantLaps replaces the laps of the drivers of the entry, so a retry doesn't duplicate them
+func (s *GormSessionStore) SaveParticipantLaps(ctx context.Context, subsessionId int, simsessionNumber int, participant irapi.DriverResult, laps []*Lap) error {
+	drivers := participantDrivers(participant)
+	custIds := make([]int, len(drivers))
+	for i, driver := range drivers {
+		custIds[i] = driver.CustId
+	}
+
+	dbLaps := make([]*events_models.Lap, 0, len(laps))
+	for _, lap := range laps {
+		// A lap of a driver without a participant row would break the foreign key
+		if !slices.Contains(custIds, lap.CustId) {
+			continue
+		}
+
+		dbLaps = append(dbLaps, &events_models.Lap{
+			SubsessionID:     subsessionId,
+			SimsessionNumber: simsessionNumber,
+			CustID:           lap.CustId,
+			LapEvents:        pq.StringArray(lap.LapEvents),
+			Incident:         lap.Incident,
+			LapTime:          lap.LapTime,
+			LapNumber:        lap.LapNumber,
+		})
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("subsession_id = ? AND simsession_number = ? AND cust_id IN ?", subsessionId, simsessionNumber, custIds).
+			Delete(&events_models.Lap{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(dbLaps) > 0 {
+			if err := tx.Create(dbLaps).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&events_models.SessionSimsessionParticipant{}).
+			Where("subsession_id = ? AND simsession_number = ? AND cust_id IN ?", subsessionId, simsessionNumber, custIds).
+			Update("laps_parsed", true).
+			Error
+	})
+}
+
+// ParsedEvents returns all the simsessions, since SaveEvents doesn't write anything
+func (s *GormSessionStore) ParsedEvents(ctx context.Context, results *irapi.ResultsResponse) (map[int]bool, error) {
+	parsedEvents := make(map[int]bool, len(results.SessionResults))
+	for _, simSessionResult := range results.SessionResults {
+		parsedEvents[simSessionResult.SimsessionNumber] = true
+	}
+	return parsedEvents, nil
+}
+
+// SaveEvents doesn't write anything: the events tables don't store the race control timeline
+func (s *GormSessionStore) SaveEvents(ctx context.Context, subsessionId int, simsessionNumber int, events []irapi.ResultsEventLogEntry) error {
+	return nil
+}
+
+func (s *GormSessionStore) SetParsed(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&events_models.Session{}).
+		Where("subsession_id = ?", results.SubsessionId).
+		Update("parsed", true).
+		Error
+}
+
+// participantDrivers returns the drivers of a team, or the participant itself
+func participantDrivers(result irapi.DriverResult) []irapi.DriverResult {
+	if result.IsTeam() {
+		return result.DriverResults
+	}
+	return []irapi.DriverResult{result}
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+type memorySessionStore struct {
+	parsed             bool
+	parsedParticipants map[ParticipantKey]bool
+	parsedEvents       map[int]bool
+	savedLaps          int
+}
+
+func (m *memorySessionStore) IsParsed(ctx context.Context, subsessionId int) (bool, error) {
+	return m.parsed, nil
+}
+
+func (m *memorySessionStore) SaveResults(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	return nil
+}
+
+func (m *memorySessionStore) ParsedParticipants(ctx context.Context, results *irapi.ResultsResponse) (map[ParticipantKey]bool, error) {
+	parsedParticipants := make(map[ParticipantKey]bool, len(m.parsedParticipants))
+	for key := range m.parsedParticipants {
+		parsedParticipants[key] = true
+	}
+	return parsedParticipants, nil
+}
+
+func (m *memorySessionStore) SaveParticipantLaps(ctx context.Context, subsessionId int, simsessionNumber int, participant irapi.DriverResult, laps []*Lap) error {
+	m.savedLaps += len(laps)
+	return nil
+}
+
+func (m *memorySessionStore) ParsedEvents(ctx context.Context, results *irapi.ResultsResponse) (map[int]bool, error) {
+	parsedEvents := make(map[int]bool, len(m.parsedEvents))
+	for simsessionNumber := range m.parsedEvents {
+		parsedEvents[simsessionNumber] = true
+	}
+	return parsedEvents, nil
+}
+
+func (m *memorySessionStore) SaveEvents(ctx context.Context, subsessionId int, simsessionNumber int, events []irapi.ResultsEventLogEntry) error {
+	return nil
+}
+
+func (m *memorySessionStore) SetParsed(ctx context.Context, results *irapi.ResultsResponse, launchAt time.Time) error {
+	m.parsed = true
+	return nil
+}
+
+func TestMultiSessionStore(t *testing.T) {
+	ctx := context.Background()
+
+	firestoreStore := &memorySessionStore{
+		parsed: true,
+		parsedParticipants: map[ParticipantKey]bool{
+			NewParticipantKey(0, 10): true,
+			NewParticipantKey(0, 20): true,
+		},
+		parsedEvents: map[int]bool{-1: true, 0: true},
+	}
+	postgresStore := &memorySessionStore{
+		parsedParticipants: map[ParticipantKey]bool{
+			NewParticipantKey(0, 20): true,
+		},
+		parsedEvents: map[int]bool{0: true},
+	}
+	store := NewMultiSessionStore(firestoreStore, postgresStore)
+
+	parsed, err := store.IsParsed(ctx, 1)
+	if err != nil || parsed {
+		t.Fatalf("expected the session not parsed, got %v, %v", parsed, err)
+	}
+
+	parsedParticipants, err := store.ParsedParticipants(ctx, &irapi.ResultsResponse{})
+	if err != nil {
+		t.Fatalf("store.ParsedParticipants: %v", err)
+	}
+	if len(parsedParticipants) != 1 || !parsedParticipants[NewParticipantKey(0, 20)] {
+		t.Fatalf("expected only the participant parsed in both stores, got %v", parsedParticipants)
+	}
+
+	parsedEvents, err := store.ParsedEvents(ctx, &irapi.ResultsResponse{})
+	if err != nil {
+		t.Fatalf("store.ParsedEvents: %v", err)
+	}
+	if len(parsedEvents) != 1 || !parsedEvents[0] {
+		t.Fatalf("expected only the events parsed in both stores, got %v", parsedEvents)
+	}
+
+	err = store.SaveParticipantLaps(ctx, 1, 0, irapi.DriverResult{CustId: 10}, []*Lap{{CustId: 10, LapNumber: 1}})
+	if err != nil {
+		t.Fatalf("store.SaveParticipantLaps: %v", err)
+	}
+	if firestoreStore.savedLaps != 1 || postgresStore.savedLaps != 1 {
+		t.Fatalf("expected the laps saved in both stores, got %d and %d", firestoreStore.savedLaps, postgresStore.savedLaps)
+	}
+
+	if err := store.SetParsed(ctx, &irapi.ResultsResponse{}, time.Now()); err != nil {
+		t.Fatalf("store.SetParsed: %v", err)
+	}
+	if parsed, _ := store.IsParsed(ctx, 1); !parsed {
+		t.Fatal("expected the session parsed")
+	}
+}
+
+func TestParseStoreNames(t *testing.T) {
+	names, err := ParseStoreNames("")
+	if err != nil || len(names) != 1 || names[0] != StoreFirestore {
+		t.Fatalf("expected firestore by default, got %v, %v", names, err)
+	}
+
+	names, err = ParseStoreNames("firestore, Postgres")
+	if err != nil || len(names) != 2 || names[1] != StorePostgres {
+		t.Fatalf("unexpected stores: %v, %v", names, err)
+	}
+
+	if _, err := ParseStoreNames("mongodb"); err == nil {
+		t.Fatal("expected an error for an invalid store")
+	}
+}
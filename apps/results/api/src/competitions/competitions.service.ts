@@ -3,7 +3,9 @@ import { CollectionReference } from '@google-cloud/firestore';
 import { CompetitionDocument } from './documents/competition.document';
 import {
   IRacingSessionDocument,
+  IRacingSessionParticipantLapsDocument,
   Lap,
+  SimsessionType,
 } from './documents/iracing_session.document';
 import { Timestamp } from '@google-cloud/firestore';
 import * as dayjs from 'dayjs';
@@ -129,19 +131,50 @@ export class CompetitionsService {
 
     const groupDriverResults = {};
 
-    sessions.forEach((s) => {
+    for (const s of sessions.docs) {
       const session = s.data();
 
       for (const simsession of session.simsessions) {
         // TODO: variable allowed simsession types
-        if (simsession.simsessionName !== 'QUALIFY') {
+        if (
+          simsession.simsessionType !== SimsessionType.LoneQualifying &&
+          simsession.simsessionType !== SimsessionType.OpenQualifying
+        ) {
           continue;
         }
 
-        for (const participant of simsession.participants) {
-          // TODO: check if the car is allowed
+        // TODO: check if the car is allowed
+        const participants = simsession.participants;
+        if (participants.length === 0) {
+          continue;
+        }
 
-          const avgLapTime = this.extractAverageLapTime(participant.laps);
+        // The laps are in a subcollection of the simsession, by entry id
+        const participantsCollection = s.ref
+          .collection(IRacingSessionDocument.simsessionsCollectionName)
+          .doc(String(simsession.simsessionNumber))
+          .collection(IRacingSessionDocument.participantsCollectionName);
+        const participantDocs = await s.ref.firestore.getAll(
+          ...participants.map((participant) =>
+            participantsCollection.doc(
+              String(participant.teamId || participant.custId),
+            ),
+          ),
+        );
+
+        for (let i = 0; i < participants.length; i++) {
+          const participant = participants[i];
+          const participantDoc = participantDocs[i];
+
+          // The old sessions have the laps in the session document
+          let laps = participant.laps || [];
+          if (participantDoc.exists) {
+            const participantLaps =
+              participantDoc.data() as IRacingSessionParticipantLapsDocument;
+            laps = participantLaps.laps;
+          }
+
+          const avgLapTime = this.extractAverageLapTime(laps);
           if (
             avgLapTime &&
             (!groupDriverResults[participant.custId] ||
@@ -151,7 +184,7 @@ export class CompetitionsService {
           }
         }
       }
-    });
+    }
 
     return groupDriverResults;
   }
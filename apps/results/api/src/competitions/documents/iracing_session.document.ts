@@ -2,34 +2,175 @@ import { Timestamp } from '@google-cloud/firestore';
 
 export class IRacingSessionDocument {
   static collectionName = 'iracing_sessions';
+  static simsessionsCollectionName = 'simsessions';
+  static participantsCollectionName = 'participants';
 
   parsed: boolean;
 
   leagueId: number;
   seasonId: number;
   launchAt: Timestamp;
+  trackId: number;
+
+  trackName: string;
+  trackConfigName: string;
+  trackState: TrackState;
+  weather: Weather;
+
+  eventStrengthOfField: number;
+  carClasses: CarClass[];
+  cautionType: number;
+  numCautions: number;
+  numCautionLaps: number;
+  numLeadChanges: number;
 
   simsessions: SimSession[];
 }
 
+class TrackState {
+  leaveMarbles: boolean;
+  practiceRubber: number;
+  qualifyRubber: number;
+  warmupRubber: number;
+  raceRubber: number;
+  practiceGripCompound: number;
+  qualifyGripCompound: number;
+  warmupGripCompound: number;
+  raceGripCompound: number;
+}
+
+class Weather {
+  type: number;
+  tempUnits: number;
+  tempValue: number;
+  relHumidity: number;
+  fog: number;
+  allowFog: boolean;
+  skies: number;
+  windUnits: number;
+  windValue: number;
+  windDir: number;
+  precipOption: number;
+  precipTimePct: number;
+  trackWater: number;
+  weatherVarInitial: number;
+  weatherVarOngoing: number;
+  simulatedStartTime: string;
+}
+
+class CarClass {
+  carClassId: number;
+  name: string;
+  shortName: string;
+  strengthOfField: number;
+  numEntries: number;
+  cars: number[];
+}
+
+// The simsession_type of the iRacing results
+export enum SimsessionType {
+  OpenPractice = 3,
+  LoneQualifying = 4,
+  OpenQualifying = 5,
+  Race = 6,
+}
+
 class SimSession {
   simsessionNumber: number;
-  simsessionType: number;
+  simsessionType: SimsessionType;
   simsessionName: string;
 
+  weather: SimSessionWeather | null;
+
   participants: Participant[];
 }
 
+class SimSessionWeather {
+  avgSkies: number;
+  avgCloudCoverPct: number;
+  tempUnits: number;
+  avgTemp: number;
+  minTemp: number;
+  maxTemp: number;
+  avgRelHumidity: number;
+  windUnits: number;
+  avgWindSpeed: number;
+  avgWindDir: number;
+  maxFog: number;
+  precipTimePct: number;
+  precipMm: number;
+  simulatedStartTime: string;
+}
+
 class Participant {
   custId: number;
   carId: number;
+  carClassId: number;
+
+  finishPosition: number;
+  finishPositionInClass: number;
+  startingPosition: number;
+  startingPositionInClass: number;
+  interval: number;
+  classInterval: number;
+  lapsComplete: number;
+  lapsLead: number;
+  incidents: number;
+  bestLapTime: number;
+  bestLapNum: number;
+  averageLap: number;
+  champPoints: number;
+  reasonOutId: number;
+  reasonOut: string;
+
+  oldIRating: number;
+  newIRating: number;
+  oldLicenseLevel: number;
+  newLicenseLevel: number;
+  oldSubLevel: number;
+  newSubLevel: number;
 
+  // Only in team events
+  teamId?: number;
+  teamName?: string;
+  drivers?: number[];
+
+  // Only in the sessions saved before the participants subcollection
+  laps?: Lap[];
+}
+
+// iracing_sessions/{id}/simsessions/{simsessionNumber}
+export class IRacingSessionSimsessionDocument {
+  simsessionNumber: number;
+  events: SessionEvent[];
+}
+
+// iracing_sessions/{id}/simsessions/{simsessionNumber}/participants/{entryId}
+export class IRacingSessionParticipantLapsDocument {
+  simsessionNumber: number;
+  entryId: number;
+  custId: number;
+  teamId?: number;
+  carId: number;
   laps: Lap[];
 }
 
 export class Lap {
+  custId: number;
   lapEvents: string[];
   incident: boolean;
   lapTime: number;
   lapNumber: number;
 }
+
+export class SessionEvent {
+  sessionTime: number;
+  eventSeq: number;
+  eventCode: number;
+  custId: number;
+  groupId: number;
+  displayName: string;
+  lapNumber: number;
+  description: string;
+  message: string;
+}
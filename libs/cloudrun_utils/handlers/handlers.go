@@ -1,12 +1,40 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
 )
 
 func ReturnException(w http.ResponseWriter, err error, functionName string) {
 	slog.Error(fmt.Sprintf("%s: %v", functionName, err))
 	w.WriteHeader(http.StatusInternalServerError)
 }
+
+// Nack the message without reporting an error, e.g. when a dependency asks to slow down.
+func ReturnRetryLater(w http.ResponseWriter, err error, functionName string) {
+	slog.Warn(fmt.Sprintf("%s: %v", functionName, err))
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+// Ack a message that can never be processed, so that it is not delivered again.
+func ReturnDiscarded(w http.ResponseWriter, err error, functionName string) {
+	slog.Error(fmt.Sprintf("%s: discarding the message: %v", functionName, err))
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReturnError answers according to the iRacing API error: retry later when rate limited,
+// discard the message when it can never succeed, else report the exception.
+func ReturnError(w http.ResponseWriter, err error, functionName string) {
+	switch {
+	case errors.Is(err, irapi.ErrRateLimited):
+		ReturnRetryLater(w, err, functionName)
+	case irapi.IsPermanent(err):
+		ReturnDiscarded(w, err, functionName)
+	default:
+		ReturnException(w, err, functionName)
+	}
+}
@@ -6,8 +6,11 @@ require (
 	ariga.io/atlas-provider-gorm v0.5.0
 	github.com/lib/pq v1.10.9
 	gorm.io/gorm v1.25.12
+	riccardotornesello.it/sharedtelemetry/iracing/irapi v0.0.0-00010101000000-000000000000
 )
 
+replace riccardotornesello.it/sharedtelemetry/iracing/irapi => ../irapi
+
 require (
 	ariga.io/atlas-go-sdk v0.2.3 // indirect
 	github.com/go-sql-driver/mysql v1.7.0 // indirect
@@ -0,0 +1,61 @@
+package events_models
+
+import (
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// Converters from the iRacing API responses
+
+func NewSession(results *irapi.ResultsResponse, launchAt time.Time) *Session {
+	return &Session{
+		SubsessionID: results.SubsessionId,
+		LeagueID:     results.LeagueId,
+		SeasonID:     results.SeasonId,
+		LaunchAt:     launchAt,
+		TrackID:      results.Track.TrackId,
+	}
+}
+
+func NewSessionSimsession(subsessionId int, result irapi.SessionResult) *SessionSimsession {
+	return &SessionSimsession{
+		SubsessionID:     subsessionId,
+		SimsessionNumber: result.SimsessionNumber,
+		SimsessionType:   int(result.SimsessionType),
+		SimsessionName:   result.SimsessionName,
+	}
+}
+
+func NewSessionSimsessionParticipant(subsessionId int, simsessionNumber int, result irapi.DriverResult) *SessionSimsessionParticipant {
+	return &SessionSimsessionParticipant{
+		SubsessionID:     subsessionId,
+		SimsessionNumber: simsessionNumber,
+		CustID:           result.CustId,
+		CarID:            result.CarId,
+		CarClassID:       result.CarClassId,
+
+		FinishPosition:          result.FinishPosition,
+		FinishPositionInClass:   result.FinishPositionInClass,
+		StartingPosition:        result.StartingPosition,
+		StartingPositionInClass: result.StartingPositionInClass,
+		Interval:                result.Interval,
+		ClassInterval:           result.ClassInterval,
+		LapsComplete:            result.LapsComplete,
+		LapsLead:                result.LapsLead,
+		Incidents:               result.Incidents,
+		BestLapTime:             result.BestLapTime,
+		BestLapNum:              result.BestLapNum,
+		AverageLap:              result.AverageLap,
+		ChampPoints:             result.ChampPoints,
+		ReasonOutID:             result.ReasonOutId,
+		ReasonOut:               result.ReasonOut,
+
+		OldIRating:      result.OldiRating,
+		NewIRating:      result.NewiRating,
+		OldLicenseLevel: result.OldLicenseLevel,
+		NewLicenseLevel: result.NewLicenseLevel,
+		OldSubLevel:     result.OldSubLevel,
+		NewSubLevel:     result.NewSubLevel,
+	}
+}
@@ -0,0 +1,2 @@
+-- Modify "session_simsession_participants" table
+ALTER TABLE "public"."session_simsession_participants" ADD COLUMN "car_class_id" bigint NULL, ADD COLUMN "finish_position" bigint NULL, ADD COLUMN "finish_position_in_class" bigint NULL, ADD COLUMN "starting_position" bigint NULL, ADD COLUMN "starting_position_in_class" bigint NULL, ADD COLUMN "interval" bigint NULL, ADD COLUMN "class_interval" bigint NULL, ADD COLUMN "laps_complete" bigint NULL, ADD COLUMN "laps_lead" bigint NULL, ADD COLUMN "incidents" bigint NULL, ADD COLUMN "best_lap_time" bigint NULL, ADD COLUMN "best_lap_num" bigint NULL, ADD COLUMN "average_lap" bigint NULL, ADD COLUMN "champ_points" bigint NULL, ADD COLUMN "reason_out_id" bigint NULL, ADD COLUMN "reason_out" text NULL, ADD COLUMN "old_i_rating" bigint NULL, ADD COLUMN "new_i_rating" bigint NULL, ADD COLUMN "old_license_level" bigint NULL, ADD COLUMN "new_license_level" bigint NULL, ADD COLUMN "old_sub_level" bigint NULL, ADD COLUMN "new_sub_level" bigint NULL;
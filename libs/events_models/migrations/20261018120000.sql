@@ -0,0 +1,4 @@
+-- Modify "sessions" table
+ALTER TABLE "public"."sessions" ADD COLUMN "parsed" boolean NOT NULL DEFAULT false;
+-- Modify "session_simsession_participants" table
+ALTER TABLE "public"."session_simsession_participants" ADD COLUMN "laps_parsed" boolean NOT NULL DEFAULT false;
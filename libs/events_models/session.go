@@ -19,4 +19,7 @@ type Session struct {
 
 	LaunchAt time.Time `gorm:"index"`
 	TrackID  int
+
+	// Set when the laps of all the participants are saved
+	Parsed bool `gorm:"not null;default:false"`
 }
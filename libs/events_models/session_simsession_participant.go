@@ -18,5 +18,34 @@ type SessionSimsessionParticipant struct {
 
 	SessionSimsession SessionSimsession `gorm:"foreignKey:SubsessionID,SimsessionNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 
-	CarID int
+	CarID      int
+	CarClassID int
+
+	// Results
+	FinishPosition          int
+	FinishPositionInClass   int
+	StartingPosition        int
+	StartingPositionInClass int
+	Interval                int
+	ClassInterval           int
+	LapsComplete            int
+	LapsLead                int
+	Incidents               int
+	BestLapTime             int
+	BestLapNum              int
+	AverageLap              int
+	ChampPoints             int
+	ReasonOutID             int
+	ReasonOut               string
+
+	// Rating changes
+	OldIRating      int
+	NewIRating      int
+	OldLicenseLevel int
+	NewLicenseLevel int
+	OldSubLevel     int
+	NewSubLevel     int
+
+	// Set when the laps of the participant are saved, so an interrupted download resumes from the missing ones
+	LapsParsed bool `gorm:"not null;default:false"`
 }
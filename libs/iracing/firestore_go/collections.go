@@ -1,7 +1,36 @@
 package firestore_structs
 
+import (
+	"strconv"
+	"time"
+)
+
 const LeaguesCollection = "iracing_leagues"
 const SeasonsCollection = "iracing_seasons"
 const DriversCollection = "iracing_drivers"
 const CarsCollection = "iracing_cars"
 const CarClassesCollection = "iracing_car_classes"
+const SessionsCollection = "iracing_sessions"
+
+// Subcollection of the drivers with the changes of their stats: iracing_drivers/{custId}/history/{date}_{category}
+const DriverHistoryCollection = "history"
+
+// DriverHistoryID keeps a single change a day for each category, so a resumed run doesn't duplicate it
+func DriverHistoryID(category string, date time.Time) string {
+	return date.UTC().Format("2006-01-02") + "_" + category
+}
+
+// Progress of the drivers downloader, one document for each category and one for all of them
+const DriverStatsCheckpointsCollection = "iracing_driver_stats_checkpoints"
+
+// Subcollections of the sessions: iracing_sessions/{subsessionId}/simsessions/{simsessionNumber}/participants/{entryId}
+const SessionSimsessionsCollection = "simsessions"
+const SessionParticipantsCollection = "participants"
+
+func SessionSimsessionID(simsessionNumber int) string {
+	return strconv.Itoa(simsessionNumber)
+}
+
+func SessionParticipantID(entryId int) string {
+	return strconv.Itoa(entryId)
+}
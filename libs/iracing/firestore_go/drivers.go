@@ -1,5 +1,11 @@
 package firestore_structs
 
+import (
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
 type Driver struct {
 	Name     string `firestore:"name"`
 	Location string `firestore:"location"`
@@ -17,6 +23,75 @@ type DriverStats struct {
 }
 
 type DriverStatsDetails struct {
-	License string `firestore:"not null"`
-	IRating int    `firestore:"not null"`
+	License string `firestore:"license"`
+	IRating int    `firestore:"iRating"`
+}
+
+func (s *DriverStats) Get(category irapi.Category) *DriverStatsDetails {
+	switch category {
+	case irapi.CategoryDirtOval:
+		return s.DirtOval
+	case irapi.CategoryDirtRoad:
+		return s.DirtRoad
+	case irapi.CategoryFormulaCar:
+		return s.FormulaCar
+	case irapi.CategoryOval:
+		return s.Oval
+	case irapi.CategoryRoad:
+		return s.Road
+	case irapi.CategorySportsCar:
+		return s.SportsCar
+	}
+	return nil
+}
+
+func (s *DriverStats) Set(category irapi.Category, details *DriverStatsDetails) {
+	switch category {
+	case irapi.CategoryDirtOval:
+		s.DirtOval = details
+	case irapi.CategoryDirtRoad:
+		s.DirtRoad = details
+	case irapi.CategoryFormulaCar:
+		s.FormulaCar = details
+	case irapi.CategoryOval:
+		s.Oval = details
+	case irapi.CategoryRoad:
+		s.Road = details
+	case irapi.CategorySportsCar:
+		s.SportsCar = details
+	}
+}
+
+// DriverStatsField returns the field of DriverStats with the stats of a category
+func DriverStatsField(category irapi.Category) string {
+	switch category {
+	case irapi.CategoryDirtOval:
+		return "dirtOval"
+	case irapi.CategoryDirtRoad:
+		return "dirtRoad"
+	case irapi.CategoryFormulaCar:
+		return "formulaCar"
+	case irapi.CategoryOval:
+		return "oval"
+	case irapi.CategoryRoad:
+		return "road"
+	case irapi.CategorySportsCar:
+		return "sportsCar"
+	}
+	return ""
+}
+
+// DriverStatsCheckpoint is the progress of the download of a category, to resume after a crash
+type DriverStatsCheckpoint struct {
+	Rows      int       `firestore:"rows"`   // Rows of the CSV already written
+	CustID    int       `firestore:"custId"` // Driver of the last written row, to check that the CSV didn't change
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// DriverStatsHistory is a change of the stats of a driver in a category, in iracing_drivers/{custId}/history
+type DriverStatsHistory struct {
+	Category string    `firestore:"category"`
+	License  string    `firestore:"license"`
+	IRating  int       `firestore:"iRating"`
+	Date     time.Time `firestore:"date"`
 }
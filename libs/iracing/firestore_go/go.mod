@@ -1,3 +1,7 @@
 module riccardotornesello.it/sharedtelemetry/iracing/firestore
 
 go 1.23.2
+
+require riccardotornesello.it/sharedtelemetry/iracing/irapi v0.0.0-00010101000000-000000000000
+
+replace riccardotornesello.it/sharedtelemetry/iracing/irapi => ../../irapi
@@ -0,0 +1,240 @@
+package firestore_structs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+)
+
+// Converters from the iRacing API responses
+
+// NewSession creates a session with its simsessions and participants, without laps and events.
+func NewSession(results *irapi.ResultsResponse, launchAt time.Time) *Session {
+	session := &Session{
+		LeagueID: results.LeagueId,
+		SeasonID: results.SeasonId,
+		LaunchAt: launchAt,
+		TrackID:  results.Track.TrackId,
+
+		TrackName:       results.Track.TrackName,
+		TrackConfigName: results.Track.ConfigName,
+		TrackState:      NewSessionTrackState(results.TrackState),
+		Weather:         NewSessionWeather(results.Weather),
+
+		EventStrengthOfField: results.EventStrengthOfField,
+		CarClasses:           make([]*SessionCarClass, len(results.CarClasses)),
+		CautionType:          results.CautionType,
+		NumCautions:          results.NumCautions,
+		NumCautionLaps:       results.NumCautionLaps,
+		NumLeadChanges:       results.NumLeadChanges,
+
+		Simsessions: make([]*SessionSimsession, len(results.SessionResults)),
+	}
+
+	for i, carClass := range results.CarClasses {
+		session.CarClasses[i] = NewSessionCarClass(carClass)
+	}
+
+	for i, result := range results.SessionResults {
+		session.Simsessions[i] = NewSessionSimsession(result)
+	}
+
+	return session
+}
+
+func NewSessionTrackState(trackState irapi.TrackState) *SessionTrackState {
+	return &SessionTrackState{
+		LeaveMarbles:         trackState.LeaveMarbles,
+		PracticeRubber:       trackState.PracticeRubber,
+		QualifyRubber:        trackState.QualifyRubber,
+		WarmupRubber:         trackState.WarmupRubber,
+		RaceRubber:           trackState.RaceRubber,
+		PracticeGripCompound: trackState.PracticeGripCompound,
+		QualifyGripCompound:  trackState.QualifyGripCompound,
+		WarmupGripCompound:   trackState.WarmupGripCompound,
+		RaceGripCompound:     trackState.RaceGripCompound,
+	}
+}
+
+func NewSessionWeather(weather irapi.Weather) *SessionWeather {
+	return &SessionWeather{
+		Type:               weather.Type,
+		TempUnits:          weather.TempUnits,
+		TempValue:          weather.TempValue,
+		RelHumidity:        weather.RelHumidity,
+		Fog:                weather.Fog,
+		AllowFog:           weather.AllowFog,
+		Skies:              weather.Skies,
+		WindUnits:          weather.WindUnits,
+		WindValue:          weather.WindValue,
+		WindDir:            weather.WindDir,
+		PrecipOption:       weather.PrecipOption,
+		PrecipTimePct:      weather.PrecipTimePct,
+		TrackWater:         weather.TrackWater,
+		WeatherVarInitial:  weather.WeatherVarInitial,
+		WeatherVarOngoing:  weather.WeatherVarOngoing,
+		SimulatedStartTime: weather.SimulatedStartTime,
+	}
+}
+
+func NewSessionCarClass(carClass irapi.ResultsCarClass) *SessionCarClass {
+	sessionCarClass := &SessionCarClass{
+		CarClassID:      carClass.CarClassId,
+		Name:            carClass.Name,
+		ShortName:       carClass.ShortName,
+		StrengthOfField: carClass.StrengthOfField,
+		NumEntries:      carClass.NumEntries,
+		Cars:            make([]int, len(carClass.CarsInClass)),
+	}
+
+	for i, car := range carClass.CarsInClass {
+		sessionCarClass.Cars[i] = car.CarId
+	}
+
+	return sessionCarClass
+}
+
+// NewSimsessionWeather returns nil if the results don't include the weather of the simsession.
+func NewSimsessionWeather(weather *irapi.WeatherResult) *SimsessionWeather {
+	if weather == nil {
+		return nil
+	}
+
+	return &SimsessionWeather{
+		AvgSkies:           weather.AvgSkies,
+		AvgCloudCoverPct:   weather.AvgCloudCoverPct,
+		TempUnits:          weather.TempUnits,
+		AvgTemp:            weather.AvgTemp,
+		MinTemp:            weather.MinTemp,
+		MaxTemp:            weather.MaxTemp,
+		AvgRelHumidity:     weather.AvgRelHumidity,
+		WindUnits:          weather.WindUnits,
+		AvgWindSpeed:       weather.AvgWindSpeed,
+		AvgWindDir:         weather.AvgWindDir,
+		MaxFog:             weather.MaxFog,
+		PrecipTimePct:      weather.PrecipTimePct,
+		PrecipMm:           weather.PrecipMm,
+		SimulatedStartTime: weather.SimulatedStartTime,
+	}
+}
+
+func NewSessionSimsession(result irapi.SessionResult) *SessionSimsession {
+	simsession := &SessionSimsession{
+		SimsessionNumber: result.SimsessionNumber,
+		SimsessionType:   int(result.SimsessionType),
+		SimsessionName:   result.SimsessionName,
+
+		Weather: NewSimsessionWeather(result.WeatherResult),
+
+		Participants: make([]*SessionSimsessionParticipant, len(result.Results)),
+	}
+
+	for i, participant := range result.Results {
+		simsession.Participants[i] = NewSessionSimsessionParticipant(participant)
+	}
+
+	return simsession
+}
+
+func NewSessionSimsessionParticipant(result irapi.DriverResult) *SessionSimsessionParticipant {
+	participant := &SessionSimsessionParticipant{
+		CustID:     result.CustId,
+		CarID:      result.CarId,
+		CarClassID: result.CarClassId,
+
+		FinishPosition:          result.FinishPosition,
+		FinishPositionInClass:   result.FinishPositionInClass,
+		StartingPosition:        result.StartingPosition,
+		StartingPositionInClass: result.StartingPositionInClass,
+		Interval:                result.Interval,
+		ClassInterval:           result.ClassInterval,
+		LapsComplete:            result.LapsComplete,
+		LapsLead:                result.LapsLead,
+		Incidents:               result.Incidents,
+		BestLapTime:             result.BestLapTime,
+		BestLapNum:              result.BestLapNum,
+		AverageLap:              result.AverageLap,
+		ChampPoints:             result.ChampPoints,
+		ReasonOutID:             result.ReasonOutId,
+		ReasonOut:               result.ReasonOut,
+
+		OldIRating:      result.OldiRating,
+		NewIRating:      result.NewiRating,
+		OldLicenseLevel: result.OldLicenseLevel,
+		NewLicenseLevel: result.NewLicenseLevel,
+		OldSubLevel:     result.OldSubLevel,
+		NewSubLevel:     result.NewSubLevel,
+	}
+
+	if result.IsTeam() {
+		participant.TeamID = result.TeamId
+		participant.TeamName = result.DisplayName
+		participant.Drivers = make([]int, len(result.DriverResults))
+		for i, driver := range result.DriverResults {
+			participant.Drivers[i] = driver.CustId
+		}
+	}
+
+	return participant
+}
+
+// EntryID identifies the participant in the lap data, like irapi.DriverResult.EntryId.
+func (p *SessionSimsessionParticipant) EntryID() int {
+	if p.TeamID != 0 {
+		return p.TeamID
+	}
+	return p.CustID
+}
+
+func NewSessionParticipantLaps(simsessionNumber int, result irapi.DriverResult, laps []*Lap) *SessionParticipantLaps {
+	return &SessionParticipantLaps{
+		SimsessionNumber: simsessionNumber,
+		EntryID:          result.EntryId(),
+		CustID:           result.CustId,
+		TeamID:           result.TeamId,
+		CarID:            result.CarId,
+		Laps:             laps,
+	}
+}
+
+func NewSessionEvent(event irapi.ResultsEventLogEntry) *SessionEvent {
+	return &SessionEvent{
+		SessionTime: event.SessionTime,
+		EventSeq:    event.EventSeq,
+		EventCode:   event.EventCode,
+		CustID:      event.CustId,
+		GroupID:     event.GroupId,
+		DisplayName: event.DisplayName,
+		LapNumber:   event.LapNumber,
+		Description: event.Description,
+		Message:     event.Message,
+	}
+}
+
+func NewCar(car irapi.CarResponse, assets irapi.CarAssetsResponse) Car {
+	return Car{
+		Name:            car.CarName,
+		NameAbbreviated: car.CarNameAbbreviated,
+		Brand:           strings.ToUpper(car.CarMake),
+		Logo:            assets.Logo,
+		SmallImage:      assets.SmallImage,
+		SponsorLogo:     assets.SponsorLogo,
+	}
+}
+
+func NewCarClass(carClass irapi.CarClassResponse) CarClass {
+	dbCarClass := CarClass{
+		Name:      carClass.Name,
+		ShortName: carClass.ShortName,
+
+		Cars: make([]string, 0, len(carClass.CarsInClass)),
+	}
+
+	for _, carInClass := range carClass.CarsInClass {
+		dbCarClass.Cars = append(dbCarClass.Cars, fmt.Sprintf("%d", carInClass.CarId))
+	}
+
+	return dbCarClass
+}
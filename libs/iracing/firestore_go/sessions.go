@@ -10,27 +10,175 @@ type Session struct {
 	LaunchAt time.Time `firestore:"launchAt"`
 	TrackID  int       `firestore:"trackId"`
 
+	TrackName       string             `firestore:"trackName"`
+	TrackConfigName string             `firestore:"trackConfigName"`
+	TrackState      *SessionTrackState `firestore:"trackState"`
+	Weather         *SessionWeather    `firestore:"weather"`
+
+	EventStrengthOfField int                `firestore:"eventStrengthOfField"`
+	CarClasses           []*SessionCarClass `firestore:"carClasses"`
+	CautionType          int                `firestore:"cautionType"`
+	NumCautions          int                `firestore:"numCautions"`
+	NumCautionLaps       int                `firestore:"numCautionLaps"`
+	NumLeadChanges       int                `firestore:"numLeadChanges"`
+
 	Simsessions []*SessionSimsession `firestore:"simsessions"`
 }
 
+// SessionTrackState is the rubber on the track at the start of each part of the session.
+type SessionTrackState struct {
+	LeaveMarbles         bool `firestore:"leaveMarbles"`
+	PracticeRubber       int  `firestore:"practiceRubber"`
+	QualifyRubber        int  `firestore:"qualifyRubber"`
+	WarmupRubber         int  `firestore:"warmupRubber"`
+	RaceRubber           int  `firestore:"raceRubber"`
+	PracticeGripCompound int  `firestore:"practiceGripCompound"`
+	QualifyGripCompound  int  `firestore:"qualifyGripCompound"`
+	WarmupGripCompound   int  `firestore:"warmupGripCompound"`
+	RaceGripCompound     int  `firestore:"raceGripCompound"`
+}
+
+// SessionWeather is the weather set for the session.
+type SessionWeather struct {
+	Type               int    `firestore:"type"`
+	TempUnits          int    `firestore:"tempUnits"`
+	TempValue          int    `firestore:"tempValue"`
+	RelHumidity        int    `firestore:"relHumidity"`
+	Fog                int    `firestore:"fog"`
+	AllowFog           bool   `firestore:"allowFog"`
+	Skies              int    `firestore:"skies"`
+	WindUnits          int    `firestore:"windUnits"`
+	WindValue          int    `firestore:"windValue"`
+	WindDir            int    `firestore:"windDir"`
+	PrecipOption       int    `firestore:"precipOption"`
+	PrecipTimePct      int    `firestore:"precipTimePct"`
+	TrackWater         int    `firestore:"trackWater"`
+	WeatherVarInitial  int    `firestore:"weatherVarInitial"`
+	WeatherVarOngoing  int    `firestore:"weatherVarOngoing"`
+	SimulatedStartTime string `firestore:"simulatedStartTime"`
+}
+
+// IsDynamic reports whether the weather changes during the session.
+func (w *SessionWeather) IsDynamic() bool {
+	return w.WeatherVarInitial != 0 || w.WeatherVarOngoing != 0
+}
+
+// IsWet reports whether the session starts on a wet track or can have rain.
+func (w *SessionWeather) IsWet() bool {
+	return w.TrackWater > 0 || w.PrecipTimePct > 0
+}
+
+type SessionCarClass struct {
+	CarClassID      int    `firestore:"carClassId"`
+	Name            string `firestore:"name"`
+	ShortName       string `firestore:"shortName"`
+	StrengthOfField int    `firestore:"strengthOfField"`
+	NumEntries      int    `firestore:"numEntries"`
+	Cars            []int  `firestore:"cars"`
+}
+
 type SessionSimsession struct {
 	SimsessionNumber int    `firestore:"simsessionNumber"`
 	SimsessionType   int    `firestore:"simsessionType"`
 	SimsessionName   string `firestore:"simsessionName"`
 
+	Weather *SimsessionWeather `firestore:"weather"`
+
+	// Without the laps, which are in the participants subcollection of the simsession
 	Participants []*SessionSimsessionParticipant `firestore:"participants"`
 }
 
+// SimsessionWeather is the weather measured during the simsession.
+type SimsessionWeather struct {
+	AvgSkies           int     `firestore:"avgSkies"`
+	AvgCloudCoverPct   float64 `firestore:"avgCloudCoverPct"`
+	TempUnits          int     `firestore:"tempUnits"`
+	AvgTemp            float64 `firestore:"avgTemp"`
+	MinTemp            float64 `firestore:"minTemp"`
+	MaxTemp            float64 `firestore:"maxTemp"`
+	AvgRelHumidity     float64 `firestore:"avgRelHumidity"`
+	WindUnits          int     `firestore:"windUnits"`
+	AvgWindSpeed       float64 `firestore:"avgWindSpeed"`
+	AvgWindDir         int     `firestore:"avgWindDir"`
+	MaxFog             float64 `firestore:"maxFog"`
+	PrecipTimePct      float64 `firestore:"precipTimePct"`
+	PrecipMm           float64 `firestore:"precipMm"`
+	SimulatedStartTime string  `firestore:"simulatedStartTime"`
+}
+
+// SessionSimsessionParticipant is a car of the simsession: a driver or, in team events, a team.
 type SessionSimsessionParticipant struct {
-	CustID int `firestore:"custId"`
-	CarID  int `firestore:"carId"`
+	CustID     int `firestore:"custId"`
+	CarID      int `firestore:"carId"`
+	CarClassID int `firestore:"carClassId"`
 
-	Laps []*Lap `firestore:"laps"`
+	FinishPosition          int    `firestore:"finishPosition"`
+	FinishPositionInClass   int    `firestore:"finishPositionInClass"`
+	StartingPosition        int    `firestore:"startingPosition"`
+	StartingPositionInClass int    `firestore:"startingPositionInClass"`
+	Interval                int    `firestore:"interval"`
+	ClassInterval           int    `firestore:"classInterval"`
+	LapsComplete            int    `firestore:"lapsComplete"`
+	LapsLead                int    `firestore:"lapsLead"`
+	Incidents               int    `firestore:"incidents"`
+	BestLapTime             int    `firestore:"bestLapTime"`
+	BestLapNum              int    `firestore:"bestLapNum"`
+	AverageLap              int    `firestore:"averageLap"`
+	ChampPoints             int    `firestore:"champPoints"`
+	ReasonOutID             int    `firestore:"reasonOutId"`
+	ReasonOut               string `firestore:"reasonOut"`
+
+	OldIRating      int `firestore:"oldIRating"`
+	NewIRating      int `firestore:"newIRating"`
+	OldLicenseLevel int `firestore:"oldLicenseLevel"`
+	NewLicenseLevel int `firestore:"newLicenseLevel"`
+	OldSubLevel     int `firestore:"oldSubLevel"`
+	NewSubLevel     int `firestore:"newSubLevel"`
+
+	// Only in team events
+	TeamID   int    `firestore:"teamId,omitempty"`
+	TeamName string `firestore:"teamName,omitempty"`
+	Drivers  []int  `firestore:"drivers,omitempty"`
 }
 
 type Lap struct {
+	CustID    int      `firestore:"custId"` // The driver of the lap, which changes in team events
 	LapEvents []string `firestore:"lapEvents"`
 	Incident  bool     `firestore:"incident"`
 	LapTime   int      `firestore:"lapTime"`
 	LapNumber int      `firestore:"lapNumber"`
+
+	// Only when downloaded from the lap chart
+	LapPosition int `firestore:"lapPosition,omitempty"`
+}
+
+// SessionEvent is an entry of the race control timeline, e.g. a caution or a penalty.
+type SessionEvent struct {
+	SessionTime int    `firestore:"sessionTime"`
+	EventSeq    int    `firestore:"eventSeq"`
+	EventCode   int    `firestore:"eventCode"`
+	CustID      int    `firestore:"custId"`
+	GroupID     int    `firestore:"groupId"`
+	DisplayName string `firestore:"displayName"`
+	LapNumber   int    `firestore:"lapNumber"`
+	Description string `firestore:"description"`
+	Message     string `firestore:"message"`
+}
+
+// SessionSimsessionDetails is a document of the simsessions subcollection of a session.
+// The details are kept out of the session document, which has a limited size.
+type SessionSimsessionDetails struct {
+	SimsessionNumber int             `firestore:"simsessionNumber"`
+	Events           []*SessionEvent `firestore:"events"`
+}
+
+// SessionParticipantLaps is a document of the participants subcollection of a simsession.
+type SessionParticipantLaps struct {
+	SimsessionNumber int `firestore:"simsessionNumber"`
+	EntryID          int `firestore:"entryId"`
+	CustID           int `firestore:"custId"`
+	TeamID           int `firestore:"teamId,omitempty"`
+	CarID            int `firestore:"carId"`
+
+	Laps []*Lap `firestore:"laps"`
 }
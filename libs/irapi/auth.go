@@ -0,0 +1,150 @@
+package irapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Authenticator is a strategy to log in to the iRacing data API.
+type Authenticator interface {
+	// Authenticate obtains new credentials. It is called when the client is created and every time
+	// iRacing reports the session as expired, never concurrently.
+	Authenticate(ctx context.Context, client *http.Client, baseURL string) error
+
+	// Authorize adds the credentials to a request to the members API. The S3 downloads are not authorized.
+	Authorize(req *http.Request)
+}
+
+type AuthEventType int
+
+const (
+	// The first login, when the client is created
+	AuthEventLogin AuthEventType = iota
+	// iRacing answered 401 to a call made with a valid session
+	AuthEventSessionExpired
+	// The client logged in again after the session expired
+	AuthEventRelogin
+	// A login failed. Err carries the reason
+	AuthEventFailed
+)
+
+func (t AuthEventType) String() string {
+	switch t {
+	case AuthEventLogin:
+		return "login"
+	case AuthEventSessionExpired:
+		return "session expired"
+	case AuthEventRelogin:
+		return "relogin"
+	case AuthEventFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("AuthEventType(%d)", int(t))
+	}
+}
+
+type AuthEvent struct {
+	Type AuthEventType
+	Err  error
+}
+
+// WithAuthHook reports the authentication events, e.g. to log or count session expirations.
+// The hook is called synchronously and must not call the client.
+func WithAuthHook(hook func(AuthEvent)) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.authHook = hook
+	}
+}
+
+func (c *IRacingApiClient) reportAuthEvent(eventType AuthEventType, err error) {
+	if c.authHook != nil {
+		c.authHook(AuthEvent{Type: eventType, Err: err})
+	}
+}
+
+func (c *IRacingApiClient) login(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if err := c.authenticate(ctx); err != nil {
+		c.reportAuthEvent(AuthEventFailed, err)
+		return err
+	}
+
+	c.authVersion.Add(1)
+	c.reportAuthEvent(AuthEventLogin, nil)
+	return nil
+}
+
+// reauthenticate logs in again after a call failed with 401. The concurrent calls that failed
+// with the same session wait for the first one to log in, instead of logging in again.
+func (c *IRacingApiClient) reauthenticate(ctx context.Context, failedVersion uint64) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.authVersion.Load() != failedVersion {
+		return nil
+	}
+
+	c.reportAuthEvent(AuthEventSessionExpired, nil)
+	c.logger.Info("iRacing session expired, logging in again")
+
+	if err := c.authenticate(ctx); err != nil {
+		c.reportAuthEvent(AuthEventFailed, err)
+		return err
+	}
+
+	c.authVersion.Add(1)
+	c.reportAuthEvent(AuthEventRelogin, nil)
+	return nil
+}
+
+func (c *IRacingApiClient) authenticate(ctx context.Context) error {
+	reqCtx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	return c.auth.Authenticate(reqCtx, c.client, c.baseURL)
+}
+
+const (
+	AuthMethodPassword                = "password"
+	AuthMethodOAuth2Password          = "oauth2_password"
+	AuthMethodOAuth2ClientCredentials = "oauth2_client_credentials"
+)
+
+// AuthConfig selects and configures an Authenticator, e.g. from environment variables.
+type AuthConfig struct {
+	// One of the AuthMethod constants. Empty means AuthMethodPassword
+	Method string
+
+	Email    string
+	Password string
+
+	ClientID     string
+	ClientSecret string
+
+	// Optional, defaults to DefaultOAuth2TokenURL
+	TokenURL string
+}
+
+func NewAuthenticator(config AuthConfig) (Authenticator, error) {
+	var auth *OAuth2Authenticator
+
+	switch config.Method {
+	case "", AuthMethodPassword:
+		return NewPasswordAuthenticator(config.Email, config.Password), nil
+	case AuthMethodOAuth2Password:
+		auth = NewOAuth2PasswordAuthenticator(config.ClientID, config.ClientSecret, config.Email, config.Password)
+	case AuthMethodOAuth2ClientCredentials:
+		auth = NewOAuth2ClientCredentialsAuthenticator(config.ClientID, config.ClientSecret)
+	default:
+		return nil, fmt.Errorf("invalid iRacing auth method: %s", config.Method)
+	}
+
+	if config.TokenURL != "" {
+		auth.TokenURL = config.TokenURL
+	}
+
+	return auth, nil
+}
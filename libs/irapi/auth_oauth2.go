@@ -0,0 +1,165 @@
+package irapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultOAuth2TokenURL = "https://oauth.iracing.com/oauth2/token"
+const DefaultOAuth2Scope = "iracing.auth"
+
+const (
+	OAuth2GrantPasswordLimited   = "password_limited"
+	OAuth2GrantClientCredentials = "client_credentials"
+)
+
+// OAuth2Authenticator obtains a bearer token from iRacing's OAuth2 server, with the
+// password_limited grant (client + user credentials) or the client_credentials one.
+// The client secret and the password are masked before being sent, as iRacing requires.
+type OAuth2Authenticator struct {
+	TokenURL     string
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+
+	// Only for the password_limited grant
+	Username string
+	Password string
+
+	mu                    sync.RWMutex
+	accessToken           string
+	refreshToken          string
+	refreshTokenExpiresAt time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken           string `json:"access_token"`
+	TokenType             string `json:"token_type"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
+	Scope                 string `json:"scope"`
+	Error                 string `json:"error"`
+	ErrorDescription      string `json:"error_description"`
+}
+
+func NewOAuth2PasswordAuthenticator(clientId string, clientSecret string, username string, password string) *OAuth2Authenticator {
+	return &OAuth2Authenticator{
+		TokenURL:     DefaultOAuth2TokenURL,
+		GrantType:    OAuth2GrantPasswordLimited,
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Scope:        DefaultOAuth2Scope,
+		Username:     username,
+		Password:     password,
+	}
+}
+
+func NewOAuth2ClientCredentialsAuthenticator(clientId string, clientSecret string) *OAuth2Authenticator {
+	return &OAuth2Authenticator{
+		TokenURL:     DefaultOAuth2TokenURL,
+		GrantType:    OAuth2GrantClientCredentials,
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Scope:        DefaultOAuth2Scope,
+	}
+}
+
+func (a *OAuth2Authenticator) Authenticate(ctx context.Context, client *http.Client, baseURL string) error {
+	a.mu.RLock()
+	refreshToken := a.refreshToken
+	refreshTokenValid := refreshToken != "" && time.Now().Before(a.refreshTokenExpiresAt)
+	a.mu.RUnlock()
+
+	// Prefer the refresh token, which does not need the user credentials
+	if refreshTokenValid {
+		form := url.Values{}
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", a.ClientID)
+		form.Set("client_secret", maskSecret(a.ClientSecret, a.ClientID))
+		form.Set("refresh_token", refreshToken)
+
+		if err := a.requestToken(ctx, client, form); err == nil {
+			return nil
+		}
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", a.GrantType)
+	form.Set("client_id", a.ClientID)
+	form.Set("client_secret", maskSecret(a.ClientSecret, a.ClientID))
+	if a.Scope != "" {
+		form.Set("scope", a.Scope)
+	}
+
+	switch a.GrantType {
+	case OAuth2GrantPasswordLimited:
+		form.Set("username", a.Username)
+		form.Set("password", maskSecret(a.Password, a.Username))
+	case OAuth2GrantClientCredentials:
+	default:
+		return fmt.Errorf("unsupported OAuth2 grant type: %s", a.GrantType)
+	}
+
+	return a.requestToken(ctx, client, form)
+}
+
+func (a *OAuth2Authenticator) requestToken(ctx context.Context, client *http.Client, form url.Values) error {
+	tokenURL := a.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultOAuth2TokenURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	tokenResponse := &oauth2TokenResponse{}
+	decodeErr := json.NewDecoder(resp.Body).Decode(tokenResponse)
+
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && tokenResponse.Error != "" {
+			return fmt.Errorf("error authenticating: %w: %s %s", newStatusError(tokenURL, resp), tokenResponse.Error, tokenResponse.ErrorDescription)
+		}
+		return fmt.Errorf("error authenticating: %w", newStatusError(tokenURL, resp))
+	}
+	if decodeErr != nil {
+		return decodeErr
+	}
+	if tokenResponse.AccessToken == "" {
+		return fmt.Errorf("error authenticating: empty access token")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.accessToken = tokenResponse.AccessToken
+	a.refreshToken = tokenResponse.RefreshToken
+	a.refreshTokenExpiresAt = time.Now().Add(time.Duration(tokenResponse.RefreshTokenExpiresIn) * time.Second)
+
+	return nil
+}
+
+func (a *OAuth2Authenticator) Authorize(req *http.Request) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.accessToken)
+	}
+}
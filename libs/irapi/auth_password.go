@@ -0,0 +1,64 @@
+package irapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// PasswordAuthenticator is the legacy login: the hashed password is posted to /auth
+// and the session is kept in the cookies of the client.
+type PasswordAuthenticator struct {
+	Email    string
+	Password string
+}
+
+func NewPasswordAuthenticator(email string, password string) *PasswordAuthenticator {
+	return &PasswordAuthenticator{Email: email, Password: password}
+}
+
+func (a *PasswordAuthenticator) Authenticate(ctx context.Context, client *http.Client, baseURL string) error {
+	tokenB64 := maskSecret(a.Password, a.Email)
+
+	body, err := json.Marshal(map[string]string{"email": a.Email, "password": tokenB64})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/auth", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("error authenticating: %w", newStatusError("/auth", resp))
+	}
+
+	authResponse := &IRacingAuthResponse{}
+	err = json.NewDecoder(resp.Body).Decode(authResponse)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *PasswordAuthenticator) Authorize(req *http.Request) {}
+
+// maskSecret hashes a secret the way iRacing expects it: base64(sha256(secret + lowercase(id))).
+func maskSecret(secret string, id string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(secret + strings.ToLower(id)))
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+}
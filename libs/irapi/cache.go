@@ -0,0 +1,201 @@
+package irapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores the responses of the endpoints listed in the client's TTLs, keyed by path.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value only if it has not expired yet
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, expiresAt time.Time) error
+}
+
+// DefaultCacheTTLs covers the catalogue endpoints, which change only with the iRacing releases.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/data/car/get":      24 * time.Hour,
+	"/data/car/assets":   24 * time.Hour,
+	"/data/carclass/get": 24 * time.Hour,
+	"/data/track/get":    24 * time.Hour,
+	"/data/track/assets": 24 * time.Hour,
+}
+
+// WithCache enables the cache for the paths with a TTL, DefaultCacheTTLs unless changed with WithCacheTTL.
+func WithCache(cache Cache) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL sets the TTL of a path, e.g. "/data/car/get". Zero disables the cache for the path.
+func WithCacheTTL(path string, ttl time.Duration) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.cacheTTLs[path] = ttl
+	}
+}
+
+// getCached serves the payload from the cache. On a miss the link is reused while its S3 signature
+// is still valid, since only the calls to the members API count for the rate limit.
+func (c *IRacingApiClient) getCached(ctx context.Context, path string, ttl time.Duration) (io.ReadCloser, error) {
+	payloadKey := "payload:" + path
+	linkKey := "link:" + path
+
+	if data, ok := c.cache.Get(payloadKey); ok {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	var data []byte
+	var err error
+
+	if link, ok := c.cache.Get(linkKey); ok {
+		data, err = c.downloadBytes(ctx, string(link))
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+	}
+
+	if data == nil {
+		response, err := c.fetchLink(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Without the expiration the link can't be reused safely
+		linkExpiresAt, parseErr := time.Parse(time.RFC3339, response.Expires)
+		if parseErr == nil {
+			expiresAt := time.Now().Add(ttl)
+			if linkExpiresAt.Before(expiresAt) {
+				expiresAt = linkExpiresAt
+			}
+			c.setCache(linkKey, []byte(response.Link), expiresAt)
+		}
+
+		data, err = c.downloadBytes(ctx, response.Link)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.setCache(payloadKey, data, time.Now().Add(ttl))
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// setCache only logs the failures: the response is still valid.
+func (c *IRacingApiClient) setCache(key string, value []byte, expiresAt time.Time) {
+	if err := c.cache.Set(key, value, expiresAt); err != nil {
+		c.logger.Warn(fmt.Sprintf("Error caching %s: %v", key, err))
+	}
+}
+
+type cacheEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (e *cacheEntry) expired() bool {
+	return !time.Now().Before(e.ExpiresAt)
+}
+
+// MemoryCache keeps the entries for the lifetime of the process, e.g. a long running server.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.expired() {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = cacheEntry{Value: value, ExpiresAt: expiresAt}
+
+	return nil
+}
+
+// FileCache persists the entries in a directory, one file per key, so they survive between runs of a job
+// or of a local development session.
+type FileCache struct {
+	dir string
+}
+
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileCache{dir: dir}, nil
+}
+
+func (f *FileCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	content, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := cacheEntry{}
+	if err := json.Unmarshal(content, &entry); err != nil || entry.expired() {
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+func (f *FileCache) Set(key string, value []byte, expiresAt time.Time) error {
+	content, err := json.Marshal(cacheEntry{Value: value, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	// Write and rename, so concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(f.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
@@ -1,6 +1,9 @@
 package irapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type CarAssetsResponse struct {
 	CarId    int `json:"car_id"`
@@ -60,12 +63,13 @@ type CarResponse struct {
 	Sku                     int      `json:"sku"`
 }
 
-func (client *IRacingApiClient) GetCarAssets() (map[int]CarAssetsResponse, error) {
+func (client *IRacingApiClient) GetCarAssets(ctx context.Context) (map[int]CarAssetsResponse, error) {
 	url := "/data/car/assets"
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := map[int]CarAssetsResponse{}
 	err = json.NewDecoder(respBody).Decode(&response)
@@ -76,12 +80,13 @@ func (client *IRacingApiClient) GetCarAssets() (map[int]CarAssetsResponse, error
 	return response, nil
 }
 
-func (client *IRacingApiClient) GetCars() (*[]CarResponse, error) {
+func (client *IRacingApiClient) GetCars(ctx context.Context) (*[]CarResponse, error) {
 	url := "/data/car/get"
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := &[]CarResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
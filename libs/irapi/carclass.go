@@ -1,6 +1,9 @@
 package irapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type CarClassResponse struct {
 	CarClassId  int `json:"car_class_id"`
@@ -17,12 +20,13 @@ type CarClassResponse struct {
 	ShortName     string `json:"short_name"`
 }
 
-func (client *IRacingApiClient) GetCarClasses() (*[]CarClassResponse, error) {
+func (client *IRacingApiClient) GetCarClasses(ctx context.Context) (*[]CarClassResponse, error) {
 	url := "/data/carclass/get"
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := &[]CarClassResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
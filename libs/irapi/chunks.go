@@ -0,0 +1,126 @@
+package irapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const DefaultChunkConcurrency = 4
+
+// WithChunkConcurrency limits the chunks downloaded (and kept in memory) at the same time for a single call.
+func WithChunkConcurrency(concurrency int) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.chunkConcurrency = max(concurrency, 1)
+	}
+}
+
+type chunkResult struct {
+	data []byte
+	err  error
+}
+
+// iterChunks downloads the chunks concurrently and decodes their items in order, one at a time,
+// so only the raw chunks being prefetched are kept in memory.
+func iterChunks[T any](ctx context.Context, c *IRacingApiClient, chunkInfo *IRacingChunkInfo, fn func(T) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan chunkResult, len(chunkInfo.ChunkFileNames))
+	for i := range results {
+		results[i] = make(chan chunkResult, 1)
+	}
+
+	// A slot is released only when the chunk has been decoded, to bound the prefetch
+	slots := make(chan struct{}, c.chunkConcurrency)
+
+	go func() {
+		for i, chunkFileName := range chunkInfo.ChunkFileNames {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] <- chunkResult{err: ctx.Err()}
+				continue
+			}
+
+			go func() {
+				data, err := c.downloadChunk(ctx, chunkInfo.BaseDownloadUrl+chunkFileName)
+				results[i] <- chunkResult{data: data, err: err}
+			}()
+		}
+	}()
+
+	for i := range results {
+		result := <-results[i]
+		if result.err != nil {
+			return fmt.Errorf("error downloading chunk %d of %d: %w", i+1, len(results), result.err)
+		}
+
+		if err := decodeArray(bytes.NewReader(result.data), fn); err != nil {
+			return err
+		}
+
+		<-slots
+	}
+
+	return nil
+}
+
+// downloadChunk reads a whole chunk, retrying the failures of S3 with the client's retry policy.
+func (c *IRacingApiClient) downloadChunk(ctx context.Context, url string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		data, err := c.downloadBytes(ctx, url)
+		if err == nil || ctx.Err() != nil || !c.retryPolicy.canRetry(attempt) {
+			return data, err
+		}
+
+		if err := sleep(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// downloadBytes reads a whole presigned S3 object.
+func (c *IRacingApiClient) downloadBytes(ctx context.Context, url string) ([]byte, error) {
+	body, err := c.download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUpstream, err)
+	}
+
+	return data, nil
+}
+
+// decodeArray streams the items of a JSON array to fn, without decoding the whole array at once.
+func decodeArray[T any](r io.Reader, fn func(T) error) error {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array, got %v", token)
+	}
+
+	for decoder.More() {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
@@ -2,22 +2,42 @@ package irapi
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/cookiejar"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const DefaultBaseURL = "https://members-ng.iracing.com"
+const DefaultTimeout = 60 * time.Second
+
 type IRacingApiClient struct {
-	client     *http.Client
-	retryAfter time.Time
+	client  *http.Client
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+
+	retryPolicy RetryPolicy
+	limiter     *RateLimiter
+
+	chunkConcurrency int
+
+	cache     Cache
+	cacheTTLs map[string]time.Duration
+
+	auth        Authenticator
+	authMu      sync.Mutex
+	authVersion atomic.Uint64
+	authHook    func(AuthEvent)
 }
 
 type IRacingAuthResponse struct {
@@ -25,7 +45,8 @@ type IRacingAuthResponse struct {
 }
 
 type IRacingResponse struct {
-	Link string `json:"link"`
+	Link    string `json:"link"`
+	Expires string `json:"expires"`
 }
 
 type IRacingChunkInfo struct {
@@ -36,120 +57,246 @@ type IRacingChunkInfo struct {
 	ChunkFileNames  []string `json:"chunk_file_names"`
 }
 
-func NewIRacingApiClient(email string, password string) (*IRacingApiClient, error) {
+type ClientOption func(*IRacingApiClient)
+
+// WithBaseURL replaces the members-ng host, e.g. to point the client at a local fake server.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport sets the RoundTripper used for every outbound request, including the S3 downloads.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.client.Transport = transport
+	}
+}
+
+// WithTimeout limits the duration of each single HTTP request. The caller's context still applies.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.timeout = timeout
+	}
+}
+
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.logger = logger
+	}
+}
+
+func NewIRacingApiClient(ctx context.Context, auth Authenticator, opts ...ClientOption) (*IRacingApiClient, error) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, err
 	}
 
-	client := &http.Client{
-		Jar: jar,
+	c := &IRacingApiClient{
+		client: &http.Client{
+			Jar: jar,
+		},
+		auth:        auth,
+		baseURL:     DefaultBaseURL,
+		timeout:     DefaultTimeout,
+		logger:      slog.Default(),
+		retryPolicy: DefaultRetryPolicy,
+		limiter:     NewRateLimiter(),
+
+		chunkConcurrency: DefaultChunkConcurrency,
+
+		cacheTTLs: maps.Clone(DefaultCacheTTLs),
 	}
 
-	tokenIn := []byte(password + strings.ToLower(email))
-	hasher := sha256.New()
-	hasher.Write(tokenIn)
-	tokenHash := hasher.Sum(nil)
-	tokenB64 := base64.StdEncoding.EncodeToString(tokenHash)
+	for _, opt := range opts {
+		opt(c)
+	}
 
-	resp, err := client.Post("https://members-ng.iracing.com/auth", "application/json", strings.NewReader(`{"email":"`+email+`","password":"`+tokenB64+`"}`))
-	if err != nil {
+	if err := c.login(ctx); err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("error authenticating: %s", resp.Status)
+	return c, nil
+}
+
+// requestContext derives the context of a single HTTP request from the caller's one.
+func (c *IRacingApiClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
 
-	defer resp.Body.Close()
+	return context.WithTimeout(ctx, c.timeout)
+}
 
-	authResponse := &IRacingAuthResponse{}
-	err = json.NewDecoder(resp.Body).Decode(authResponse)
+func (c *IRacingApiClient) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	if ttl := c.cacheTTLs[path]; c.cache != nil && ttl > 0 {
+		return c.getCached(ctx, path, ttl)
+	}
+
+	response, err := c.fetchLink(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 
-	return &IRacingApiClient{
-		client: client,
-	}, nil
+	return c.download(ctx, response.Link)
 }
 
-func (c *IRacingApiClient) get(path string) (io.ReadCloser, error) {
-	var resp *http.Response
-	var err error
+// fetchLink calls the members API, handling the rate limit, the retries and the expired sessions.
+func (c *IRacingApiClient) fetchLink(ctx context.Context, path string) (*IRacingResponse, error) {
+	reauthenticated := false
 
-	for {
-		if c.retryAfter.After(time.Now()) {
-			slog.Info(fmt.Sprintf("Rate limit exceeded, waiting until %v", c.retryAfter.Format(time.RFC3339)))
-			time.Sleep(time.Until(c.retryAfter))
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx, path, c.retryPolicy.FailOnRateLimit); err != nil {
+			return nil, err
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second) // TODO: allow timeout customization
-		defer cancel()
+		authVersion := c.authVersion.Load()
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://members-ng.iracing.com"+path, nil)
-		if err != nil {
-			return nil, fmt.Errorf("error creating request for %s: %w", path, err)
+		response, retryable, err := c.getLink(ctx, path)
+		if err == nil {
+			return response, nil
 		}
 
-		resp, err = c.client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("error getting %s: %w", path, err)
-		}
+		// The session expired: log in again and replay the call, only once
+		if errors.Is(err, ErrUnauthorized) && !reauthenticated {
+			reauthenticated = true
 
-		if resp.StatusCode != 429 {
-			break
+			if err := c.reauthenticate(ctx, authVersion); err != nil {
+				return nil, err
+			}
+			continue
 		}
 
-		slog.Info(fmt.Sprintf("Rate limit exceeded for %s, retrying in a bit", path))
+		rateLimitErr, isRateLimit := err.(*RateLimitError)
+		if isRateLimit && !rateLimitErr.Reset.IsZero() {
+			// Let the other calls know, even when failing fast
+			c.limiter.Block(rateLimitErr.Reset)
+		}
 
-		// TODO: allow to skip retrying
-		// TODO: allow max retry count
-		rateLimitReset := resp.Header.Get("X-RateLimit-Reset")
-		if rateLimitReset == "" {
-			break
+		if !retryable || !c.retryPolicy.canRetry(attempt) {
+			return nil, err
 		}
 
-		rateLimitResetInt, err := strconv.ParseInt(rateLimitReset, 10, 64)
-		if err != nil {
-			break
+		if isRateLimit {
+			c.logger.Info(fmt.Sprintf("Rate limit exceeded for %s, retrying in a bit", path))
+
+			if !rateLimitErr.Reset.IsZero() {
+				// The next attempt waits in the limiter
+				continue
+			}
 		}
 
-		// Not atomic, but we don't care
-		c.retryAfter = time.Unix(rateLimitResetInt, 0).Add(2 * time.Second)
+		delay := c.retryPolicy.backoff(attempt)
+		c.logger.Info(fmt.Sprintf("Attempt %d for %s failed, retrying in %v: %v", attempt, path, delay, err))
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("error getting %s: %w", path, err)
+		}
 	}
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("error getting %s: %s", path, resp.Status)
+// getLink performs a single call to the members API and reports whether a failure is worth retrying.
+func (c *IRacingApiClient) getLink(ctx context.Context, path string) (*IRacingResponse, bool, error) {
+	reqCtx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		c.limiter.Done(nil)
+		return nil, false, fmt.Errorf("error creating request for %s: %w", path, err)
 	}
+	c.auth.Authorize(req)
 
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.limiter.Done(nil)
+
+		// Network errors and per-request timeouts are retried, the caller's cancellation is not
+		if ctx.Err() != nil {
+			return nil, false, fmt.Errorf("error getting %s: %w", path, err)
+		}
+		return nil, true, fmt.Errorf("error getting %s: %w: %w", path, ErrUpstream, err)
+	}
 	defer resp.Body.Close()
 
+	c.limiter.Done(resp.Header)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimitErr := newRateLimitError(path, time.Time{})
+		rateLimitErr.Status = resp.Status
+
+		rateLimitReset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err == nil {
+			rateLimitErr.Reset = time.Unix(rateLimitReset, 0).Add(2 * time.Second)
+		}
+
+		return nil, !c.retryPolicy.FailOnRateLimit, rateLimitErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode >= 500, newStatusError(path, resp)
+	}
+
 	response := &IRacingResponse{}
 	err = json.NewDecoder(resp.Body).Decode(response)
 	if err != nil {
-		return nil, err
+		return nil, ctx.Err() == nil && reqCtx.Err() != nil, fmt.Errorf("error decoding %s: %w", path, err)
 	}
 
-	payloadResp, err := c.client.Get(response.Link)
+	return response, false, nil
+}
+
+// download fetches a presigned S3 URL. The returned body must be closed by the caller.
+// The request timeout only applies until the response headers arrive: the body of a large payload,
+// e.g. a CSV consumed while writing to a database, can take longer to read.
+func (c *IRacingApiClient) download(ctx context.Context, url string) (io.ReadCloser, error) {
+	reqCtx, cancel := context.WithCancel(ctx)
+
+	var timer *time.Timer
+	if c.timeout > 0 {
+		timer = time.AfterFunc(c.timeout, cancel)
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	return payloadResp.Body, nil
-}
-
-func (c *IRacingApiClient) getChunks(chunkInfo *IRacingChunkInfo) ([]io.ReadCloser, error) {
-	out := make([]io.ReadCloser, len(chunkInfo.ChunkFileNames))
-
-	for i, chunkFileName := range chunkInfo.ChunkFileNames {
-		resp, err := c.client.Get(chunkInfo.BaseDownloadUrl + chunkFileName)
-		if err != nil {
+	resp, err := c.client.Do(req)
+	timedOut := timer != nil && !timer.Stop()
+	if timedOut && err == nil {
+		// The timer fired just after the response arrived, the body would fail at the first read
+		resp.Body.Close()
+		err = reqCtx.Err()
+	}
+	if err != nil {
+		cancel()
+		if ctx.Err() != nil {
 			return nil, err
 		}
+		if timedOut {
+			return nil, fmt.Errorf("%w: no response within %v: %w", ErrUpstream, c.timeout, context.DeadlineExceeded)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrUpstream, err)
+	}
 
-		out[i] = resp.Body
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, newDownloadError(url, resp)
 	}
 
-	return out, nil
+	return &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
@@ -1,28 +1,642 @@
 package irapi
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
 )
 
+// TestCall replays the fixtures of testdata. With IRACING_RECORD=1 it calls the real API instead,
+// with the credentials of .env, and records the responses into testdata.
 func TestCall(t *testing.T) {
-	err := godotenv.Load()
+	var client *IRacingApiClient
+	var err error
+
+	if os.Getenv("IRACING_RECORD") == "1" {
+		err := godotenv.Load()
+		if err != nil {
+			t.Fatal("Error loading .env file")
+		}
+
+		iRacingEmail := os.Getenv("IRACING_EMAIL")
+		iRacingPassword := os.Getenv("IRACING_PASSWORD")
+
+		client, err = NewIRacingApiClient(context.Background(), NewPasswordAuthenticator(iRacingEmail, iRacingPassword), WithTransport(irapitest.NewRecorder("testdata", nil)))
+		if err != nil {
+			t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+		}
+	} else {
+		server, err := irapitest.Load("testdata")
+		if err != nil {
+			t.Fatalf("irapitest.Load: %v", err)
+		}
+		defer server.Close()
+
+		client, err = NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+		}
+	}
+
+	league, err := client.GetLeague(context.Background(), 4403, false)
 	if err != nil {
-		t.Fatal("Error loading .env file")
+		t.Fatalf("client.GetLeague: %v", err)
 	}
+	if league.LeagueId != 4403 {
+		t.Fatalf("unexpected league: %+v", league)
+	}
+}
 
-	iRacingEmail := os.Getenv("IRACING_EMAIL")
-	iRacingPassword := os.Getenv("IRACING_PASSWORD")
+func TestClientOptions(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/league/get":
+			w.Write([]byte(`{"link":"` + server.URL + `/payload"}`))
+		case "/payload":
+			w.Write([]byte(`{"league_id":4403,"league_name":"Test league"}`))
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
 
-	client, err := NewIRacingApiClient(iRacingEmail, iRacingPassword)
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL+"/"),
+		WithTransport(server.Client().Transport),
+		WithTimeout(50*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
 	if err != nil {
 		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
 	}
 
-	_, err = client.GetLeague(4403, false)
+	league, err := client.GetLeague(context.Background(), 4403, false)
 	if err != nil {
 		t.Fatalf("client.GetLeague: %v", err)
 	}
+	if league.LeagueId != 4403 || league.LeagueName != "Test league" {
+		t.Fatalf("unexpected league: %+v", league)
+	}
+
+	// The per-request timeout must apply
+	_, err = client.get(context.Background(), "/slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+
+	// The caller's cancellation must apply
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetLeague(ctx, 4403, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestDownloadTimeout(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/driver_stats_by_category/road":
+			w.Write([]byte(`{"link":"` + server.URL + `/csv"}`))
+		case "/csv":
+			// The headers arrive at once, the rows slower than the timeout
+			w.Write([]byte("DRIVER,CUSTID,LOCATION,CLASS,IRATING\n"))
+			w.(http.Flusher).Flush()
+			for i := 1; i <= 3; i++ {
+				time.Sleep(50 * time.Millisecond)
+				w.Write([]byte("Driver," + strconv.Itoa(i) + ",IT,A 4.99,1500\n"))
+				w.(http.Flusher).Flush()
+			}
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL+"/"),
+		WithTransport(server.Client().Transport),
+		WithTimeout(100*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	// A slow reader outlives the timeout
+	reader, err := client.GetDriverStatsByCategory(context.Background(), CategoryRoad)
+	if err != nil {
+		t.Fatalf("client.GetDriverStatsByCategory: %v", err)
+	}
+	defer reader.Close()
+
+	rows := 0
+	for {
+		_, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reader.Read after %d rows: %v", rows, err)
+		}
+		rows++
+		time.Sleep(50 * time.Millisecond)
+	}
+	if rows != 3 {
+		t.Fatalf("expected 3 rows, got %d", rows)
+	}
+
+	// The timeout still applies to the response headers
+	_, err = client.download(context.Background(), server.URL+"/slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var server *httptest.Server
+	calls := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/car/get":
+			calls++
+			if calls < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{"link":"` + server.URL + `/payload"}`))
+		case "/payload":
+			w.Write([]byte(`[{"car_id":1}]`))
+		case "/data/carclass/get":
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, FailOnRateLimit: true}),
+	)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	// Server errors are retried
+	cars, err := client.GetCars(context.Background())
+	if err != nil {
+		t.Fatalf("client.GetCars: %v", err)
+	}
+	if len(*cars) != 1 || calls != 3 {
+		t.Fatalf("unexpected result: %d cars after %d calls", len(*cars), calls)
+	}
+
+	// The rate limit fails fast, for the current call and the following ones
+	for i := 0; i < 2; i++ {
+		_, err = client.GetCarClasses(context.Background())
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected a rate limit error, got %v", err)
+		}
+
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) || rateLimitErr.Reset.Before(time.Now()) {
+			t.Fatalf("expected the reset time in the error, got %v", err)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter()
+
+	if limiter.Budget().Known() {
+		t.Fatal("the budget must be unknown before the first response")
+	}
+
+	// Two calls in flight, the first response says only one call is left until the reset
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.Background(), "/test", true); err != nil {
+			t.Fatalf("limiter.Wait: %v", err)
+		}
+	}
+
+	reset := time.Now().Add(time.Second).Truncate(time.Second).Add(time.Second)
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "10")
+	header.Set("X-RateLimit-Remaining", "1")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	limiter.Done(header)
+
+	// The call still in flight takes the last token
+	budget := limiter.Budget()
+	if budget.Limit != 10 || budget.Remaining != 0 || !budget.Reset.Equal(reset) {
+		t.Fatalf("unexpected budget: %+v", budget)
+	}
+
+	limiter.Done(nil)
+
+	var rateLimitErr *RateLimitError
+	err := limiter.Wait(context.Background(), "/test", true)
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.Reset.Equal(reset) {
+		t.Fatalf("expected a rate limit error until the reset, got %v", err)
+	}
+
+	// Without failing fast, the call waits for the refill
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "/test", false); err != nil {
+		t.Fatalf("limiter.Wait: %v", err)
+	}
+	if time.Now().Before(reset) {
+		t.Fatal("the call did not wait for the reset")
+	}
+	if budget := limiter.Budget(); budget.Remaining != 9 {
+		t.Fatalf("unexpected budget after the refill: %+v", budget)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/results/get":
+			status, _ := strconv.Atoi(r.URL.Query().Get("subsession_id"))
+			w.WriteHeader(status)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, FailOnRateLimit: true}),
+	)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	tests := []struct {
+		status int
+		target error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrResultsRestricted},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusServiceUnavailable, ErrUpstream},
+		{http.StatusTooManyRequests, ErrRateLimited},
+	}
+
+	for _, test := range tests {
+		_, err := client.GetResults(context.Background(), test.status)
+		if !errors.Is(err, test.target) {
+			t.Errorf("status %d: expected %v, got %v", test.status, test.target, err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != test.status || apiErr.Path != "/data/results/get?subsession_id="+strconv.Itoa(test.status) {
+			t.Errorf("status %d: unexpected error details %#v", test.status, apiErr)
+		}
+	}
+}
+
+func TestReauthentication(t *testing.T) {
+	var server *httptest.Server
+	var mu sync.Mutex
+	logins := 0
+	validSession := ""
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/auth":
+			logins++
+			validSession = strconv.Itoa(logins)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: validSession, Path: "/"})
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/expire":
+			validSession = ""
+		case "/data/league/get":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != validSession {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"link":"` + server.URL + `/payload"}`))
+		case "/payload":
+			w.Write([]byte(`{"league_id":4403}`))
+		}
+	}))
+	defer server.Close()
+
+	var events []AuthEventType
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL),
+		WithAuthHook(func(event AuthEvent) {
+			events = append(events, event.Type)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	if _, err := http.Get(server.URL + "/expire"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The concurrent calls failing with the same session log in only once
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetLeague(context.Background(), 4403, false)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("client.GetLeague: %v", err)
+		}
+	}
+
+	if logins != 2 {
+		t.Fatalf("expected 2 logins, got %d", logins)
+	}
+
+	expectedEvents := []AuthEventType{AuthEventLogin, AuthEventSessionExpired, AuthEventRelogin}
+	if len(events) != len(expectedEvents) {
+		t.Fatalf("unexpected auth events: %v", events)
+	}
+	for i, event := range events {
+		if event != expectedEvents[i] {
+			t.Fatalf("unexpected auth events: %v", events)
+		}
+	}
+}
+
+func TestOAuth2Authenticator(t *testing.T) {
+	var server *httptest.Server
+	var grants []string
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			r.ParseForm()
+			grants = append(grants, r.PostForm.Get("grant_type"))
+			validPassword := r.PostForm.Get("grant_type") != OAuth2GrantPasswordLimited || r.PostForm.Get("password") == maskSecret("password", "User@example.com")
+			if r.PostForm.Get("client_secret") != maskSecret("secret", "client") || !validPassword {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"invalid_client"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"token` + strconv.Itoa(len(grants)) + `","refresh_token":"refresh","refresh_token_expires_in":3600}`))
+		case "/data/league/get":
+			if r.Header.Get("Authorization") != "Bearer token"+strconv.Itoa(len(grants)) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"link":"` + server.URL + `/payload"}`))
+		case "/payload":
+			w.Write([]byte(`{"league_id":4403}`))
+		case "/expire":
+			grants = append(grants, "expired")
+		}
+	}))
+	defer server.Close()
+
+	auth, err := NewAuthenticator(AuthConfig{
+		Method:       AuthMethodOAuth2Password,
+		Email:        "User@example.com",
+		Password:     "password",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     server.URL + "/oauth2/token",
+	})
+	if err != nil {
+		t.Fatalf("irapi.NewAuthenticator: %v", err)
+	}
+
+	client, err := NewIRacingApiClient(context.Background(), auth, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	if _, err := client.GetLeague(context.Background(), 4403, false); err != nil {
+		t.Fatalf("client.GetLeague: %v", err)
+	}
+
+	// An expired access token is replaced using the refresh token
+	if _, err := http.Get(server.URL + "/expire"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetLeague(context.Background(), 4403, false); err != nil {
+		t.Fatalf("client.GetLeague: %v", err)
+	}
+
+	expectedGrants := []string{OAuth2GrantPasswordLimited, "expired", "refresh_token"}
+	if len(grants) != len(expectedGrants) {
+		t.Fatalf("unexpected grants: %v", grants)
+	}
+	for i, grant := range grants {
+		if grant != expectedGrants[i] {
+			t.Fatalf("unexpected grants: %v", grants)
+		}
+	}
+
+	if _, err := NewAuthenticator(AuthConfig{Method: "unknown"}); err == nil {
+		t.Fatal("expected an error for an unknown auth method")
+	}
+}
+
+func TestResultsLapDataChunks(t *testing.T) {
+	var server *httptest.Server
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/results/lap_data":
+			w.Write([]byte(`{"link":"` + server.URL + `/payload?cust_id=` + r.URL.Query().Get("cust_id") + `"}`))
+		case "/payload":
+			chunks := `"chunk_0.json","chunk_1.json","chunk_2.json","chunk_3.json","chunk_4.json"`
+			if r.URL.Query().Get("cust_id") == "2" {
+				chunks += `,"missing.json"`
+			}
+			w.Write([]byte(`{"cust_id":1,"chunk_info":{"base_download_url":"` + server.URL + `/chunks/","chunk_file_names":[` + chunks + `]}}`))
+		case "/chunks/missing.json":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			// Chunks finishing out of order must still be decoded in order
+			chunk := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/chunks/chunk_"), ".json")
+			chunkNumber, _ := strconv.Atoi(chunk)
+			time.Sleep(time.Duration(5-chunkNumber) * 5 * time.Millisecond)
+			w.Write([]byte(`[{"lap_number":` + strconv.Itoa(chunkNumber*2+1) + `},{"lap_number":` + strconv.Itoa(chunkNumber*2+2) + `}]`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+		WithBaseURL(server.URL),
+		WithChunkConcurrency(3),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	lapData, err := client.GetResultsLapData(context.Background(), 1, 0, 1)
+	if err != nil {
+		t.Fatalf("client.GetResultsLapData: %v", err)
+	}
+	if len(lapData.Laps) != 10 {
+		t.Fatalf("expected 10 laps, got %d", len(lapData.Laps))
+	}
+	for i, lap := range lapData.Laps {
+		if lap.LapNumber != i+1 {
+			t.Fatalf("unexpected lap order: %+v", lapData.Laps)
+		}
+	}
+	if maxRunning < 2 || maxRunning > 3 {
+		t.Fatalf("expected between 2 and 3 concurrent downloads, got %d", maxRunning)
+	}
+
+	// A failing chunk stops the iteration
+	_, err = client.GetResultsLapData(context.Background(), 1, 0, 2)
+	if !errors.Is(err, ErrUpstream) {
+		t.Fatalf("expected an upstream error, got %v", err)
+	}
+
+	// So does an error of the callback
+	laps := 0
+	stop := errors.New("stop")
+	_, err = client.IterResultsLapData(context.Background(), 1, 0, 1, func(lap ResultsLapDataChunk) error {
+		laps++
+		if laps == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || laps != 3 {
+		t.Fatalf("expected the iteration to stop after 3 laps, got %d laps and %v", laps, err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	var server *httptest.Server
+	var mu sync.Mutex
+	linkCalls, downloads := 0, 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"authcode":"test"}`))
+		case "/data/car/get", "/data/carclass/get", "/data/league/get":
+			linkCalls++
+			expires := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			w.Write([]byte(`{"link":"` + server.URL + `/payload","expires":"` + expires + `"}`))
+		case "/payload":
+			downloads++
+			w.Write([]byte(`[{"car_id":1}]`))
+		}
+	}))
+	defer server.Close()
+
+	fileCache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("irapi.NewFileCache: %v", err)
+	}
+
+	for _, cache := range []Cache{NewMemoryCache(), fileCache} {
+		linkCalls, downloads = 0, 0
+
+		client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("email", "password"),
+			WithBaseURL(server.URL),
+			WithCache(cache),
+			WithCacheTTL("/data/carclass/get", time.Nanosecond),
+		)
+		if err != nil {
+			t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+		}
+
+		// The payload is served from the cache
+		for i := 0; i < 2; i++ {
+			if _, err := client.GetCars(context.Background()); err != nil {
+				t.Fatalf("client.GetCars: %v", err)
+			}
+		}
+		if linkCalls != 1 || downloads != 1 {
+			t.Fatalf("%T: expected 1 link call and 1 download, got %d and %d", cache, linkCalls, downloads)
+		}
+
+		// The TTL caps the reuse of the links too
+		linkCalls, downloads = 0, 0
+		for i := 0; i < 2; i++ {
+			time.Sleep(time.Millisecond)
+			if _, err := client.GetCarClasses(context.Background()); err != nil {
+				t.Fatalf("client.GetCarClasses: %v", err)
+			}
+		}
+		if linkCalls != 2 || downloads != 2 {
+			t.Fatalf("%T: expected 2 link calls and 2 downloads, got %d and %d", cache, linkCalls, downloads)
+		}
+
+		// The other endpoints are not cached
+		linkCalls, downloads = 0, 0
+		for i := 0; i < 2; i++ {
+			client.GetLeague(context.Background(), 4403, false)
+		}
+		if linkCalls != 2 {
+			t.Fatalf("%T: expected 2 link calls, got %d", cache, linkCalls)
+		}
+	}
+
+	// An expired entry is a miss
+	cache := NewMemoryCache()
+	cache.Set("key", []byte("value"), time.Now().Add(-time.Second))
+	if _, ok := cache.Get("key"); ok {
+		t.Fatal("expected a miss for an expired entry")
+	}
 }
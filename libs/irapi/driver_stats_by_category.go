@@ -1,65 +1,215 @@
 package irapi
 
 import (
+	"context"
+	"encoding/csv"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
-func (client *IRacingApiClient) GetDriverStatsByCategoryOval() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/oval"
-	body, err := client.get(url)
-	if err != nil {
-		return nil, err
+// Category is a license category of the driver stats.
+type Category string
+
+const (
+	CategoryOval       Category = "oval"
+	CategorySportsCar  Category = "sports_car"
+	CategoryFormulaCar Category = "formula_car"
+	CategoryRoad       Category = "road"
+	CategoryDirtOval   Category = "dirt_oval"
+	CategoryDirtRoad   Category = "dirt_road"
+)
+
+var Categories = []Category{
+	CategoryOval,
+	CategorySportsCar,
+	CategoryFormulaCar,
+	CategoryRoad,
+	CategoryDirtOval,
+	CategoryDirtRoad,
+}
+
+func ParseCategory(name string) (Category, error) {
+	for _, category := range Categories {
+		if string(category) == name {
+			return category, nil
+		}
 	}
+	return "", fmt.Errorf("invalid category: %v", name)
+}
+
+// DriverStatsRow is a row of the driver stats CSV.
+type DriverStatsRow struct {
+	Driver        string
+	CustId        int
+	Location      string
+	Region        string
+	ClubName      string
+	Starts        int
+	Wins          int
+	AvgStartPos   float64
+	AvgFinishPos  float64
+	AvgPoints     float64
+	Top25Pcnt     float64
+	Laps          int
+	LapsLead      int
+	AvgInc        float64
+	Class         string // License class and safety rating, e.g. "A 4.99"
+	SafetyRating  float64
+	Irating       int
+	TtRating      int
+	TotClubPoints int
+	ChampPoints   int
+}
+
+// The columns which must be in the header. The others are left empty when missing.
+var driverStatsRequiredColumns = []string{"DRIVER", "CUSTID", "LOCATION", "CLASS", "IRATING"}
 
-	return body, nil
+// DriverStatsReader reads the driver stats CSV one row at a time, mapping the columns by name.
+type DriverStatsReader struct {
+	body      io.ReadCloser
+	csvReader *csv.Reader
+	columns   map[string]int
 }
 
-func (client *IRacingApiClient) GetDriverStatsByCategorySportsCar() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/sports_car"
-	body, err := client.get(url)
+// GetDriverStatsByCategory downloads the stats of all the drivers of a category.
+// The caller must close the reader.
+func (client *IRacingApiClient) GetDriverStatsByCategory(ctx context.Context, category Category) (*DriverStatsReader, error) {
+	if _, err := ParseCategory(string(category)); err != nil {
+		return nil, err
+	}
+
+	body, err := client.get(ctx, "/data/driver_stats_by_category/"+string(category))
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	reader, err := NewDriverStatsReader(body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("error reading the driver stats of %v: %w", category, err)
+	}
+
+	return reader, nil
 }
 
-func (client *IRacingApiClient) GetDriverStatsByCategoryFormulaCar() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/formula_car"
-	body, err := client.get(url)
+// NewDriverStatsReader reads the header of the CSV and checks the required columns.
+func NewDriverStatsReader(body io.ReadCloser) (*DriverStatsReader, error) {
+	csvReader := csv.NewReader(body)
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, name := range driverStatsRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("invalid header, missing column %s: %v", name, header)
+		}
+	}
+
+	return &DriverStatsReader{body: body, csvReader: csvReader, columns: columns}, nil
 }
 
-func (client *IRacingApiClient) GetDriverStatsByCategoryRoad() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/road"
-	body, err := client.get(url)
+// Read returns the next row, or io.EOF at the end of the CSV.
+func (r *DriverStatsReader) Read() (*DriverStatsRow, error) {
+	record, err := r.csvReader.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	p := driverStatsParser{columns: r.columns, record: record}
+	row := &DriverStatsRow{
+		Driver:        p.string("DRIVER"),
+		CustId:        p.int("CUSTID"),
+		Location:      p.string("LOCATION"),
+		Region:        p.string("REGION"),
+		ClubName:      p.string("CLUB_NAME"),
+		Starts:        p.int("STARTS"),
+		Wins:          p.int("WINS"),
+		AvgStartPos:   p.float("AVG_START_POS"),
+		AvgFinishPos:  p.float("AVG_FINISH_POS"),
+		AvgPoints:     p.float("AVG_POINTS"),
+		Top25Pcnt:     p.float("TOP25PCNT"),
+		Laps:          p.int("LAPS"),
+		LapsLead:      p.int("LAPSLEAD"),
+		AvgInc:        p.float("AVG_INC"),
+		Class:         p.string("CLASS"),
+		Irating:       p.int("IRATING"),
+		TtRating:      p.int("TTRATING"),
+		TotClubPoints: p.int("TOT_CLUBPOINTS"),
+		ChampPoints:   p.int("CHAMPPOINTS"),
+	}
+
+	if _, safetyRating, ok := strings.Cut(row.Class, " "); ok {
+		row.SafetyRating = p.parseFloat("CLASS", safetyRating)
+	}
+
+	if p.err != nil {
+		line, _ := r.csvReader.FieldPos(0)
+		return nil, fmt.Errorf("line %d: %w", line, p.err)
+	}
+
+	return row, nil
+}
+
+func (r *DriverStatsReader) Close() error {
+	return r.body.Close()
+}
+
+// driverStatsParser converts the fields of a record, keeping the first error.
+type driverStatsParser struct {
+	columns map[string]int
+	record  []string
+	err     error
+}
+
+func (p *driverStatsParser) string(column string) string {
+	i, ok := p.columns[column]
+	if !ok || i >= len(p.record) {
+		return ""
+	}
+	return strings.TrimSpace(p.record[i])
 }
 
-func (client *IRacingApiClient) GetDriverStatsByCategoryDirtOval() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/dirt_oval"
-	body, err := client.get(url)
+func (p *driverStatsParser) int(column string) int {
+	value := p.string(column)
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.Atoi(value)
 	if err != nil {
-		return nil, err
+		p.fail(column, err)
 	}
+	return parsed
+}
 
-	return body, nil
+func (p *driverStatsParser) float(column string) float64 {
+	return p.parseFloat(column, p.string(column))
 }
 
-func (client *IRacingApiClient) GetDriverStatsByCategoryDirtRoad() (io.ReadCloser, error) {
-	url := "/data/driver_stats_by_category/dirt_road"
-	body, err := client.get(url)
+func (p *driverStatsParser) parseFloat(column string, value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		return nil, err
+		p.fail(column, err)
 	}
+	return parsed
+}
 
-	return body, nil
+func (p *driverStatsParser) fail(column string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %w", column, err)
+	}
 }
@@ -0,0 +1,73 @@
+package irapi
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
+)
+
+func TestDriverStatsByCategory(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	// Columns in a different order than the usual CSV
+	server.AddText("/data/driver_stats_by_category/road", strings.Join([]string{
+		`"CUSTID","DRIVER","IRATING","CLASS","LOCATION","REGION","STARTS","AVG_INC"`,
+		`"10","Max Rossi","2500","A 4.99","IT","Europe","120","1.75"`,
+		`"20","John Smith","1350","D 2.10","US","","3",""`,
+	}, "\n"))
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	reader, err := client.GetDriverStatsByCategory(context.Background(), CategoryRoad)
+	if err != nil {
+		t.Fatalf("client.GetDriverStatsByCategory: %v", err)
+	}
+	defer reader.Close()
+
+	row, err := reader.Read()
+	if err != nil {
+		t.Fatalf("reader.Read: %v", err)
+	}
+	if row.CustId != 10 || row.Driver != "Max Rossi" || row.Irating != 2500 || row.Class != "A 4.99" || row.SafetyRating != 4.99 ||
+		row.Location != "IT" || row.Region != "Europe" || row.Starts != 120 || row.AvgInc != 1.75 {
+		t.Fatalf("unexpected row: %+v", row)
+	}
+
+	row, err = reader.Read()
+	if err != nil {
+		t.Fatalf("reader.Read: %v", err)
+	}
+	if row.CustId != 20 || row.Region != "" || row.AvgInc != 0 {
+		t.Fatalf("unexpected row: %+v", row)
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestDriverStatsReaderErrors(t *testing.T) {
+	_, err := NewDriverStatsReader(io.NopCloser(strings.NewReader("DRIVER,CUSTID,LOCATION,CLASS\n")))
+	if err == nil || !strings.Contains(err.Error(), "IRATING") {
+		t.Fatalf("expected an error for the missing column, got %v", err)
+	}
+
+	reader, err := NewDriverStatsReader(io.NopCloser(strings.NewReader("DRIVER,CUSTID,LOCATION,CLASS,IRATING\nMax,ten,IT,A 4.99,2500\n")))
+	if err != nil {
+		t.Fatalf("NewDriverStatsReader: %v", err)
+	}
+	if _, err := reader.Read(); err == nil || !strings.Contains(err.Error(), "CUSTID") {
+		t.Fatalf("expected an error for the invalid cust id, got %v", err)
+	}
+
+	if _, err := ParseCategory("karting"); err == nil {
+		t.Fatal("expected an error for an invalid category")
+	}
+}
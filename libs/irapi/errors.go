@@ -0,0 +1,91 @@
+package irapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrNotFound          = errors.New("not found")
+	ErrResultsRestricted = errors.New("results restricted")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrUpstream          = errors.New("upstream error")
+)
+
+// APIError is returned for every unsuccessful response of iRacing. Err is one of the sentinels above,
+// so callers can use errors.Is, or nil for unclassified statuses.
+type APIError struct {
+	Path       string
+	StatusCode int
+	Status     string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("error getting %s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("error getting %s: %s", e.Path, e.Status)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when the rate limit is exhausted and the client is configured to fail fast,
+// or when the retry policy runs out of attempts. Reset is zero if iRacing did not provide it.
+type RateLimitError struct {
+	APIError
+	Reset time.Time
+}
+
+func newRateLimitError(path string, reset time.Time) *RateLimitError {
+	return &RateLimitError{
+		APIError: APIError{Path: path, StatusCode: http.StatusTooManyRequests, Err: ErrRateLimited},
+		Reset:    reset,
+	}
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("error getting %s: %v", e.Path, ErrRateLimited)
+	}
+
+	return fmt.Sprintf("error getting %s: %v until %s", e.Path, ErrRateLimited, e.Reset.Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return &e.APIError
+}
+
+func newStatusError(path string, resp *http.Response) error {
+	apiErr := &APIError{Path: path, StatusCode: resp.StatusCode, Status: resp.Status}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		apiErr.Err = ErrUnauthorized
+	case resp.StatusCode == http.StatusForbidden:
+		// iRacing refuses the results of private leagues and hosted sessions
+		apiErr.Err = ErrResultsRestricted
+	case resp.StatusCode == http.StatusNotFound:
+		apiErr.Err = ErrNotFound
+	case resp.StatusCode >= 500:
+		apiErr.Err = ErrUpstream
+	}
+
+	return apiErr
+}
+
+// The S3 links are presigned, so any failure is on iRacing's side.
+func newDownloadError(url string, resp *http.Response) error {
+	return &APIError{Path: url, StatusCode: resp.StatusCode, Status: resp.Status, Err: ErrUpstream}
+}
+
+// IsPermanent reports whether retrying the same call can't succeed, e.g. for a missing subsession.
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrResultsRestricted)
+}
@@ -0,0 +1,75 @@
+package irapitest
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fixture is the recorded response of a members API path. Body is the payload behind the link
+// and Chunks, if any, the content of the files listed in its chunk_info. Text replaces Body
+// for the payloads which aren't JSON, e.g. the CSVs of the driver stats.
+type Fixture struct {
+	Path   string            `json:"path"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"`
+	Chunks []json.RawMessage `json:"chunks,omitempty"`
+}
+
+// fixtureKey normalizes a path with its query, so the order of the parameters doesn't matter.
+func fixtureKey(path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+
+	query := u.Query().Encode()
+	if query == "" {
+		return u.Path
+	}
+
+	return u.Path + "?" + query
+}
+
+// fixtureFileName derives a readable file name from the path,
+// e.g. data_league_get_include_licenses_false_league_id_4403.json
+func fixtureFileName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, fixtureKey(path))
+
+	return strings.Trim(name, "_") + ".json"
+}
+
+func ReadFixture(fileName string) (*Fixture, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	if err := json.Unmarshal(content, fixture); err != nil {
+		return nil, err
+	}
+
+	return fixture, nil
+}
+
+// WriteFixture saves the fixture in dir, with a file name derived from its path.
+func WriteFixture(dir string, fixture *Fixture) error {
+	content, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, fixtureFileName(fixture.Path)), content, 0o644)
+}
@@ -0,0 +1,135 @@
+package irapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Recorder is a RoundTripper capturing the responses of the real API into fixtures, to be used as
+// the transport of a client: irapi.WithTransport(irapitest.NewRecorder("testdata", nil)).
+// The login is never recorded.
+type Recorder struct {
+	transport http.RoundTripper
+	dir       string
+
+	mu      sync.Mutex
+	links   map[string]*Fixture
+	chunks  map[string]recordedChunk
+	lastErr error
+}
+
+type recordedChunkInfo struct {
+	BaseDownloadUrl string   `json:"base_download_url"`
+	ChunkFileNames  []string `json:"chunk_file_names"`
+}
+
+type recordedChunk struct {
+	fixture *Fixture
+	index   int
+}
+
+// NewRecorder writes the fixtures in dir. A nil transport means http.DefaultTransport.
+func NewRecorder(dir string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{
+		transport: transport,
+		dir:       dir,
+		links:     make(map[string]*Fixture),
+		chunks:    make(map[string]recordedChunk),
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.String()
+
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/data/"):
+		// The members API answers with the link to the payload
+		response := struct {
+			Link string `json:"link"`
+		}{}
+		if json.Unmarshal(body, &response) == nil && response.Link != "" {
+			r.links[response.Link] = &Fixture{Path: req.URL.RequestURI()}
+		}
+
+	case r.links[url] != nil:
+		fixture := r.links[url]
+		delete(r.links, url)
+
+		if !json.Valid(body) {
+			fixture.Text = string(body)
+			r.write(fixture)
+			break
+		}
+		fixture.Body = body
+
+		payload := struct {
+			ChunkInfo *recordedChunkInfo `json:"chunk_info"`
+			Data      struct {
+				ChunkInfo *recordedChunkInfo `json:"chunk_info"`
+			} `json:"data"`
+		}{}
+		if json.Unmarshal(body, &payload) == nil {
+			chunkInfo := payload.ChunkInfo
+			if chunkInfo == nil {
+				chunkInfo = payload.Data.ChunkInfo
+			}
+
+			if chunkInfo != nil {
+				fixture.Chunks = make([]json.RawMessage, len(chunkInfo.ChunkFileNames))
+				for i, chunkFileName := range chunkInfo.ChunkFileNames {
+					r.chunks[chunkInfo.BaseDownloadUrl+chunkFileName] = recordedChunk{fixture: fixture, index: i}
+				}
+			}
+		}
+
+		r.write(fixture)
+
+	case r.chunks[url].fixture != nil:
+		chunk := r.chunks[url]
+		delete(r.chunks, url)
+
+		chunk.fixture.Chunks[chunk.index] = body
+
+		r.write(chunk.fixture)
+	}
+
+	return resp, nil
+}
+
+// The fixture is written again for every chunk, so it is complete even if the caller stops early.
+func (r *Recorder) write(fixture *Fixture) {
+	if err := WriteFixture(r.dir, fixture); err != nil {
+		r.lastErr = err
+	}
+}
+
+// Err returns the last error writing a fixture, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lastErr
+}
@@ -0,0 +1,343 @@
+// Package irapitest provides a fake of members-ng.iracing.com for the tests of irapi and of its users.
+//
+// The server answers the members API with the link indirection used by iRacing, serves the payloads
+// and their chunks as S3 would, and can simulate the rate limit. The responses come from fixtures,
+// which can be written by hand or captured from the real API with a Recorder.
+package irapitest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sessionCookie = "irsso_membersv2"
+
+// LinkExpiration is the validity of the links returned by the server.
+const LinkExpiration = 10 * time.Minute
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	fixtures map[string]*Fixture
+	ids      map[string]*Fixture
+	requests []string
+
+	sessions    map[string]bool
+	sessionsNum int
+
+	rateLimit       int
+	rateLimitWindow time.Duration
+	remaining       int
+	reset           time.Time
+}
+
+func NewServer() *Server {
+	s := &Server{
+		fixtures: make(map[string]*Fixture),
+		ids:      make(map[string]*Fixture),
+		sessions: make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth", s.handleAuth)
+	mux.HandleFunc("GET /data/", s.handleData)
+	mux.HandleFunc("GET /s3/{id}", s.handlePayload)
+	mux.HandleFunc("GET /s3/{id}/chunks/{chunk}", s.handleChunk)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+func fixtureId(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:8])
+}
+
+// AddFixture serves the fixture for its path, replacing the previous one.
+func (s *Server) AddFixture(fixture *Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fixtureKey(fixture.Path)
+	s.fixtures[key] = fixture
+	s.ids[fixtureId(key)] = fixture
+}
+
+// AddResponse is a shortcut for a fixture built from values, encoded as JSON.
+func (s *Server) AddResponse(path string, body any, chunks ...any) error {
+	fixture := &Fixture{Path: path}
+
+	var err error
+	fixture.Body, err = json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		encodedChunk, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		fixture.Chunks = append(fixture.Chunks, encodedChunk)
+	}
+
+	s.AddFixture(fixture)
+
+	return nil
+}
+
+// AddText serves a payload which isn't JSON, e.g. a CSV.
+func (s *Server) AddText(path string, text string) {
+	s.AddFixture(&Fixture{Path: path, Text: text})
+}
+
+// LoadFixtures adds every fixture found in dir, e.g. the testdata directory of a package.
+func (s *Server) LoadFixtures(dir string) error {
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, fileName := range fileNames {
+		fixture, err := ReadFixture(fileName)
+		if err != nil {
+			return fmt.Errorf("error loading fixture %s: %w", fileName, err)
+		}
+
+		s.AddFixture(fixture)
+	}
+
+	return nil
+}
+
+// SetRateLimit allows limit calls to the members API every window, like the X-RateLimit-* headers of iRacing.
+// Zero disables the rate limit.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimit = limit
+	s.rateLimitWindow = window
+	s.remaining = limit
+	s.reset = time.Time{}
+}
+
+// ExpireSessions invalidates the sessions of the logged in clients, which have to log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.sessions)
+}
+
+// Logins returns the number of successful calls to /auth.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessionsNum
+}
+
+// Requests returns the paths of the calls to the members API, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	credentials := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil || credentials.Email == "" || credentials.Password == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	s.sessionsNum++
+	session := strconv.Itoa(s.sessionsNum)
+	s.sessions[session] = true
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: session, Path: "/"})
+	writeJSON(w, map[string]string{"authcode": session})
+}
+
+func (s *Server) handleData(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.URL.RequestURI())
+
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || !s.sessions[cookie.Value] {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if !s.takeRateLimit(w.Header()) {
+		writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+		return
+	}
+
+	key := fixtureKey(r.URL.RequestURI())
+	if _, ok := s.fixtures[key]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, map[string]string{
+		"link":    s.URL + "/s3/" + fixtureId(key),
+		"expires": time.Now().Add(LinkExpiration).UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) takeRateLimit(header http.Header) bool {
+	if s.rateLimit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if !now.Before(s.reset) {
+		s.remaining = s.rateLimit
+		s.reset = now.Add(s.rateLimitWindow)
+	}
+
+	ok := s.remaining > 0
+	if ok {
+		s.remaining--
+	}
+
+	header.Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(s.remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(s.reset.Unix(), 10))
+
+	return ok
+}
+
+func (s *Server) handlePayload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	fixture, ok := s.ids[r.PathValue("id")]
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if fixture.Text != "" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(fixture.Text))
+		return
+	}
+
+	if len(fixture.Chunks) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture.Body)
+		return
+	}
+
+	chunkFileNames := make([]string, len(fixture.Chunks))
+	for i := range fixture.Chunks {
+		chunkFileNames[i] = strconv.Itoa(i) + ".json"
+	}
+
+	body, err := rewriteChunkInfo(fixture.Body, s.URL+"/s3/"+r.PathValue("id")+"/chunks/", chunkFileNames)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// rewriteChunkInfo points the chunk_info at the chunks served by this server. Like iRacing, the chunk_info
+// is either in the payload or in its data object, e.g. for the searches.
+func rewriteChunkInfo(rawBody json.RawMessage, baseDownloadUrl string, chunkFileNames []string) (json.RawMessage, error) {
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return nil, err
+	}
+
+	if _, ok := body["chunk_info"]; !ok {
+		if data, ok := body["data"]; ok {
+			rewrittenData, err := rewriteChunkInfo(data, baseDownloadUrl, chunkFileNames)
+			if err != nil {
+				return nil, err
+			}
+			body["data"] = rewrittenData
+
+			return json.Marshal(body)
+		}
+	}
+
+	chunkInfo := map[string]any{}
+	if rawChunkInfo, ok := body["chunk_info"]; ok {
+		json.Unmarshal(rawChunkInfo, &chunkInfo)
+	}
+
+	chunkInfo["base_download_url"] = baseDownloadUrl
+	chunkInfo["chunk_file_names"] = chunkFileNames
+	chunkInfo["num_chunks"] = len(chunkFileNames)
+
+	encodedChunkInfo, err := json.Marshal(chunkInfo)
+	if err != nil {
+		return nil, err
+	}
+	body["chunk_info"] = encodedChunkInfo
+
+	return json.Marshal(body)
+}
+
+func (s *Server) handleChunk(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	fixture, ok := s.ids[r.PathValue("id")]
+	s.mu.Unlock()
+
+	chunk, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("chunk"), ".json"))
+	if !ok || err != nil || chunk < 0 || chunk >= len(fixture.Chunks) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(fixture.Chunks[chunk])
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// Load is a shortcut to start a server with the fixtures of dir.
+func Load(dir string) (*Server, error) {
+	s := NewServer()
+
+	if err := s.LoadFixtures(dir); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
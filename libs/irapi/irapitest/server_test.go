@@ -0,0 +1,125 @@
+package irapitest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi"
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
+)
+
+func newClient(t *testing.T, server *irapitest.Server, opts ...irapi.ClientOption) *irapi.IRacingApiClient {
+	opts = append([]irapi.ClientOption{irapi.WithBaseURL(server.URL)}, opts...)
+
+	client, err := irapi.NewIRacingApiClient(context.Background(), irapi.NewPasswordAuthenticator("test@example.com", "password"), opts...)
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/league/get?league_id=4403&include_licenses=false", map[string]any{"league_id": 4403, "league_name": "Test league"})
+	server.AddResponse("/data/results/lap_data?subsession_id=1&simsession_number=0&cust_id=2",
+		map[string]any{"cust_id": 2, "chunk_info": map[string]any{"chunk_size": 2}},
+		[]map[string]any{{"lap_number": 1}, {"lap_number": 2}},
+		[]map[string]any{{"lap_number": 3}},
+	)
+
+	// Record the responses of the first server
+	dir := t.TempDir()
+	recorder := irapitest.NewRecorder(dir, nil)
+	client := newClient(t, server, irapi.WithTransport(recorder))
+
+	if _, err := client.GetLeague(context.Background(), 4403, false); err != nil {
+		t.Fatalf("client.GetLeague: %v", err)
+	}
+	if _, err := client.GetResultsLapData(context.Background(), 1, 0, 2); err != nil {
+		t.Fatalf("client.GetResultsLapData: %v", err)
+	}
+	if err := recorder.Err(); err != nil {
+		t.Fatalf("recorder.Err: %v", err)
+	}
+
+	// And replay them from a second one
+	replayServer, err := irapitest.Load(dir)
+	if err != nil {
+		t.Fatalf("irapitest.Load: %v", err)
+	}
+	defer replayServer.Close()
+
+	client = newClient(t, replayServer)
+
+	league, err := client.GetLeague(context.Background(), 4403, false)
+	if err != nil {
+		t.Fatalf("client.GetLeague: %v", err)
+	}
+	if league.LeagueName != "Test league" {
+		t.Fatalf("unexpected league: %+v", league)
+	}
+
+	lapData, err := client.GetResultsLapData(context.Background(), 1, 0, 2)
+	if err != nil {
+		t.Fatalf("client.GetResultsLapData: %v", err)
+	}
+	if lapData.CustId != 2 || len(lapData.Laps) != 3 || lapData.Laps[2].LapNumber != 3 {
+		t.Fatalf("unexpected lap data: %+v", lapData)
+	}
+
+	// The missing fixtures are not found
+	_, err = client.GetLeague(context.Background(), 1, false)
+	if !errors.Is(err, irapi.ErrNotFound) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/car/get", []map[string]any{{"car_id": 1}})
+	server.SetRateLimit(2, time.Hour)
+
+	retryPolicy := irapi.DefaultRetryPolicy
+	retryPolicy.FailOnRateLimit = true
+	client := newClient(t, server, irapi.WithRetryPolicy(retryPolicy))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCars(context.Background()); err != nil {
+			t.Fatalf("client.GetCars: %v", err)
+		}
+	}
+
+	budget := client.RateLimitBudget()
+	if budget.Limit != 2 || budget.Remaining != 0 {
+		t.Fatalf("unexpected budget: %+v", budget)
+	}
+
+	_, err := client.GetCars(context.Background())
+	if !errors.Is(err, irapi.ErrRateLimited) {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+}
+
+func TestExpiredSession(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/car/get", []map[string]any{{"car_id": 1}})
+
+	client := newClient(t, server)
+	server.ExpireSessions()
+
+	if _, err := client.GetCars(context.Background()); err != nil {
+		t.Fatalf("client.GetCars: %v", err)
+	}
+	if server.Logins() != 2 {
+		t.Fatalf("expected 2 logins, got %d", server.Logins())
+	}
+}
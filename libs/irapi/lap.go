@@ -0,0 +1,141 @@
+package irapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LapFlag is a bit of the flags of a lap. The bits follow the order of the lap events.
+type LapFlag int
+
+const (
+	LapFlagInvalid LapFlag = 1 << iota
+	LapFlagPitted
+	LapFlagOffTrack
+	LapFlagBlackFlag
+	LapFlagCarReset
+	LapFlagContact
+	LapFlagCarContact
+	LapFlagLostControl
+	LapFlagDiscontinuity
+	LapFlagInterpolatedCrossing
+	LapFlagClockSmash
+	LapFlagTow
+)
+
+// Has reports whether all the bits of flag are set.
+func (f LapFlag) Has(flag LapFlag) bool {
+	return f&flag == flag
+}
+
+// LapEvent is an entry of the lap_events of a lap.
+type LapEvent int
+
+const (
+	LapEventUnknown LapEvent = iota
+	LapEventInvalid
+	LapEventPitted
+	LapEventOffTrack
+	LapEventBlackFlag
+	LapEventCarReset
+	LapEventContact
+	LapEventCarContact
+	LapEventLostControl
+	LapEventDiscontinuity
+	LapEventInterpolatedCrossing
+	LapEventClockSmash
+	LapEventTow
+)
+
+var lapEventNames = map[LapEvent]string{
+	LapEventInvalid:              "invalid",
+	LapEventPitted:               "pitted",
+	LapEventOffTrack:             "off track",
+	LapEventBlackFlag:            "black flag",
+	LapEventCarReset:             "car reset",
+	LapEventContact:              "contact",
+	LapEventCarContact:           "car contact",
+	LapEventLostControl:          "lost control",
+	LapEventDiscontinuity:        "discontinuity",
+	LapEventInterpolatedCrossing: "interpolated crossing",
+	LapEventClockSmash:           "clock smash",
+	LapEventTow:                  "tow",
+}
+
+var lapEventsByName = func() map[string]LapEvent {
+	events := make(map[string]LapEvent, len(lapEventNames))
+	for event, name := range lapEventNames {
+		events[name] = event
+	}
+	return events
+}()
+
+// ParseLapEvent parses a lap event as returned by the API, e.g. "off track".
+func ParseLapEvent(name string) (LapEvent, error) {
+	event, ok := lapEventsByName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return LapEventUnknown, fmt.Errorf("unknown lap event %q", name)
+	}
+	return event, nil
+}
+
+// ParseLapEvents parses the lap events, mapping the unknown ones to LapEventUnknown.
+func ParseLapEvents(names []string) []LapEvent {
+	events := make([]LapEvent, len(names))
+	for i, name := range names {
+		events[i], _ = ParseLapEvent(name)
+	}
+	return events
+}
+
+func (e LapEvent) String() string {
+	if name, ok := lapEventNames[e]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Flag returns the lap flag matching the event, 0 for LapEventUnknown.
+func (e LapEvent) Flag() LapFlag {
+	if e == LapEventUnknown {
+		return 0
+	}
+	return 1 << (e - 1)
+}
+
+// SimsessionType is the simsession_type of the results, e.g. practice or race.
+type SimsessionType int
+
+const (
+	SimsessionTypeOpenPractice   SimsessionType = 3
+	SimsessionTypeLoneQualifying SimsessionType = 4
+	SimsessionTypeOpenQualifying SimsessionType = 5
+	SimsessionTypeRace           SimsessionType = 6
+)
+
+func (t SimsessionType) IsPractice() bool {
+	return t == SimsessionTypeOpenPractice
+}
+
+func (t SimsessionType) IsQualifying() bool {
+	return t == SimsessionTypeLoneQualifying || t == SimsessionTypeOpenQualifying
+}
+
+func (t SimsessionType) IsRace() bool {
+	return t == SimsessionTypeRace
+}
+
+func (t SimsessionType) String() string {
+	switch t {
+	case SimsessionTypeOpenPractice:
+		return "Open Practice"
+	case SimsessionTypeLoneQualifying:
+		return "Lone Qualifying"
+	case SimsessionTypeOpenQualifying:
+		return "Open Qualifying"
+	case SimsessionTypeRace:
+		return "Race"
+	default:
+		return fmt.Sprintf("SimsessionType(%d)", int(t))
+	}
+}
@@ -0,0 +1,54 @@
+package irapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLapFlags(t *testing.T) {
+	var lap ResultsLapDataChunk
+	err := json.Unmarshal([]byte(`{"flags": 6, "lap_events": ["pitted", "off track"]}`), &lap)
+	if err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if !lap.Flags.Has(LapFlagPitted) || !lap.Flags.Has(LapFlagOffTrack|LapFlagPitted) || lap.Flags.Has(LapFlagInvalid) {
+		t.Fatalf("unexpected flags: %b", lap.Flags)
+	}
+
+	var flags LapFlag
+	for _, event := range ParseLapEvents(lap.LapEvents) {
+		flags |= event.Flag()
+	}
+	if flags != lap.Flags {
+		t.Fatalf("flags of the events %b, want %b", flags, lap.Flags)
+	}
+}
+
+func TestParseLapEvent(t *testing.T) {
+	for event := LapEventInvalid; event <= LapEventTow; event++ {
+		parsed, err := ParseLapEvent(event.String())
+		if err != nil || parsed != event {
+			t.Fatalf("ParseLapEvent(%q) = %v, %v", event.String(), parsed, err)
+		}
+	}
+
+	if _, err := ParseLapEvent("tyre change"); err == nil {
+		t.Fatal("expected an error for an unknown event")
+	}
+	if events := ParseLapEvents([]string{"Car Contact", "tyre change"}); events[0] != LapEventCarContact || events[1] != LapEventUnknown {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestSimsessionType(t *testing.T) {
+	var result SessionResult
+	err := json.Unmarshal([]byte(`{"simsession_type": 4, "simsession_name": "QUALIFY"}`), &result)
+	if err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if !result.SimsessionType.IsQualifying() || result.SimsessionType.IsRace() || result.SimsessionType.String() != "Lone Qualifying" {
+		t.Fatalf("unexpected simsession type: %v", result.SimsessionType)
+	}
+}
@@ -0,0 +1,266 @@
+package irapi
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+const (
+	ChartTypeIRating  = 1
+	ChartTypeTTRating = 2
+	ChartTypeLicense  = 3
+)
+
+type MembersResponse struct {
+	Success bool     `json:"success"`
+	CustIds []int    `json:"cust_ids"`
+	Members []Member `json:"members"`
+}
+
+type Member struct {
+	CustId      int       `json:"cust_id"`
+	DisplayName string    `json:"display_name"`
+	Helmet      Helmet    `json:"helmet"`
+	LastLogin   string    `json:"last_login"`
+	MemberSince string    `json:"member_since"`
+	ClubId      int       `json:"club_id"`
+	ClubName    string    `json:"club_name"`
+	Ai          bool      `json:"ai"`
+	Licenses    []License `json:"licenses"`
+}
+
+// MemberInfoResponse describes the account used by the client.
+type MemberInfoResponse struct {
+	CustId          int                `json:"cust_id"`
+	Email           string             `json:"email"`
+	Username        string             `json:"username"`
+	DisplayName     string             `json:"display_name"`
+	FirstName       string             `json:"first_name"`
+	LastName        string             `json:"last_name"`
+	OnCarName       string             `json:"on_car_name"`
+	MemberSince     string             `json:"member_since"`
+	LastTestTrack   int                `json:"last_test_track"`
+	LastTestCar     int                `json:"last_test_car"`
+	LastSeason      int                `json:"last_season"`
+	Flags           int                `json:"flags"`
+	ClubId          int                `json:"club_id"`
+	ClubName        string             `json:"club_name"`
+	ConnectionType  string             `json:"connection_type"`
+	DownloadServer  string             `json:"download_server"`
+	LastLogin       string             `json:"last_login"`
+	ReadCompRules   string             `json:"read_comp_rules"`
+	Account         MemberAccount      `json:"account"`
+	Helmet          Helmet             `json:"helmet"`
+	Suit            Suit               `json:"suit"`
+	Licenses        map[string]License `json:"licenses"`
+	CarPackages     []MemberPackage    `json:"car_packages"`
+	TrackPackages   []MemberPackage    `json:"track_packages"`
+	OtherOwnedParts []int              `json:"other_owned_parts"`
+	Dev             bool               `json:"dev"`
+	AlphaTester     bool               `json:"alpha_tester"`
+	RainTester      bool               `json:"rain_tester"`
+	Broadcaster     bool               `json:"broadcaster"`
+	HasReadPp       bool               `json:"has_read_pp"`
+	HasReadTc       bool               `json:"has_read_tc"`
+}
+
+type MemberAccount struct {
+	IrDollars    float32 `json:"ir_dollars"`
+	IrCredits    float32 `json:"ir_credits"`
+	Status       string  `json:"status"`
+	CountryRules any     `json:"country_rules"`
+}
+
+type MemberPackage struct {
+	PackageId  int   `json:"package_id"`
+	ContentIds []int `json:"content_ids"`
+}
+
+type MemberProfileResponse struct {
+	Success    bool `json:"success"`
+	CustId     int  `json:"cust_id"`
+	MemberInfo struct {
+		CustId         int       `json:"cust_id"`
+		DisplayName    string    `json:"display_name"`
+		Helmet         Helmet    `json:"helmet"`
+		LastLogin      string    `json:"last_login"`
+		MemberSince    string    `json:"member_since"`
+		ClubId         int       `json:"club_id"`
+		ClubName       string    `json:"club_name"`
+		Ai             bool      `json:"ai"`
+		Licenses       []License `json:"licenses"`
+		Country        string    `json:"country"`
+		CountryCode    string    `json:"country_code"`
+		FlairId        int       `json:"flair_id"`
+		FlairName      string    `json:"flair_name"`
+		FlairShortname string    `json:"flair_shortname"`
+	} `json:"member_info"`
+	Disabled       bool      `json:"disabled"`
+	LicenseHistory []License `json:"license_history"`
+	RecentEvents   []struct {
+		EventType        string         `json:"event_type"`
+		SubsessionId     int            `json:"subsession_id"`
+		StartTime        string         `json:"start_time"`
+		EventId          int            `json:"event_id"`
+		EventName        string         `json:"event_name"`
+		SimsessionType   SimsessionType `json:"simsession_type"`
+		StartingPosition int            `json:"starting_position"`
+		FinishPosition   int            `json:"finish_position"`
+		BestLapTime      int            `json:"best_lap_time"`
+		PercentRank      int            `json:"percent_rank"`
+		CarId            int            `json:"car_id"`
+		CarName          string         `json:"car_name"`
+		LogoUrl          string         `json:"logo_url"`
+		Track            TrackRef       `json:"track"`
+	} `json:"recent_events"`
+	Activity struct {
+		Recent02WeeksCount   int `json:"recent_02weeks_count"`
+		Prev02WeeksCount     int `json:"prev_02weeks_count"`
+		ConsecutiveWeeks     int `json:"consecutive_weeks"`
+		MostConsecutiveWeeks int `json:"most_consecutive_weeks"`
+	} `json:"activity"`
+	FollowCounts struct {
+		Followers int `json:"followers"`
+		Follows   int `json:"follows"`
+	} `json:"follow_counts"`
+	IsGenericImage bool   `json:"is_generic_image"`
+	ImageUrl       string `json:"image_url"`
+}
+
+type MemberChartDataResponse struct {
+	Blackout   bool `json:"blackout"`
+	CategoryId int  `json:"category_id"`
+	ChartType  int  `json:"chart_type"`
+	CustId     int  `json:"cust_id"`
+	Success    bool `json:"success"`
+	Data       []struct {
+		When  string `json:"when"`
+		Value int    `json:"value"`
+	} `json:"data"`
+}
+
+type MemberRecentRacesResponse struct {
+	CustId int                `json:"cust_id"`
+	Races  []MemberRecentRace `json:"races"`
+}
+
+type MemberRecentRace struct {
+	SeasonId           int      `json:"season_id"`
+	SeriesId           int      `json:"series_id"`
+	SeriesName         string   `json:"series_name"`
+	CarId              int      `json:"car_id"`
+	CarClassId         int      `json:"car_class_id"`
+	Livery             Livery   `json:"livery"`
+	LicenseLevel       int      `json:"license_level"`
+	SessionStartTime   string   `json:"session_start_time"`
+	WinnerGroupId      int      `json:"winner_group_id"`
+	WinnerName         string   `json:"winner_name"`
+	WinnerHelmet       Helmet   `json:"winner_helmet"`
+	WinnerLicenseLevel int      `json:"winner_license_level"`
+	StartPosition      int      `json:"start_position"`
+	FinishPosition     int      `json:"finish_position"`
+	QualifyingTime     int      `json:"qualifying_time"`
+	Laps               int      `json:"laps"`
+	LapsLed            int      `json:"laps_led"`
+	Incidents          int      `json:"incidents"`
+	ClubPoints         int      `json:"club_points"`
+	Points             int      `json:"points"`
+	StrengthOfField    int      `json:"strength_of_field"`
+	SubsessionId       int      `json:"subsession_id"`
+	OldSubLevel        int      `json:"old_sub_level"`
+	NewSubLevel        int      `json:"new_sub_level"`
+	OldiRating         int      `json:"oldi_rating"`
+	NewiRating         int      `json:"newi_rating"`
+	Track              TrackRef `json:"track"`
+	DropRace           bool     `json:"drop_race"`
+	SeasonYear         int      `json:"season_year"`
+	SeasonQuarter      int      `json:"season_quarter"`
+	RaceWeekNum        int      `json:"race_week_num"`
+}
+
+func (client *IRacingApiClient) GetMembers(ctx context.Context, custIds []int, includeLicenses bool) (*MembersResponse, error) {
+	url := "/data/member/get?cust_ids=" + joinInts(custIds) + "&include_licenses=" + strconv.FormatBool(includeLicenses)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &MembersResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (client *IRacingApiClient) GetMemberInfo(ctx context.Context) (*MemberInfoResponse, error) {
+	url := "/data/member/info"
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &MemberInfoResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (client *IRacingApiClient) GetMemberProfile(ctx context.Context, custId int) (*MemberProfileResponse, error) {
+	url := "/data/member/profile?cust_id=" + strconv.Itoa(custId)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &MemberProfileResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetMemberChartData returns the history of a rating in a license category, chartType is one of the ChartType constants.
+func (client *IRacingApiClient) GetMemberChartData(ctx context.Context, custId int, categoryId int, chartType int) (*MemberChartDataResponse, error) {
+	url := "/data/member/chart_data?cust_id=" + strconv.Itoa(custId) + "&category_id=" + strconv.Itoa(categoryId) + "&chart_type=" + strconv.Itoa(chartType)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &MemberChartDataResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (client *IRacingApiClient) GetMemberRecentRaces(ctx context.Context, custId int) (*MemberRecentRacesResponse, error) {
+	url := "/data/stats/member_recent_races?cust_id=" + strconv.Itoa(custId)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &MemberRecentRacesResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,61 @@
+package irapi
+
+import (
+	"context"
+	"testing"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
+)
+
+func TestMembers(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/member/get?cust_ids=1,2&include_licenses=true", map[string]any{
+		"success":  true,
+		"cust_ids": []int{1, 2},
+		"members": []map[string]any{
+			{"cust_id": 1, "display_name": "Driver One", "licenses": []map[string]any{{"category_id": 5, "irating": 2500, "safety_rating": 3.45}}},
+			{"cust_id": 2, "display_name": "Driver Two"},
+		},
+	})
+	server.AddResponse("/data/member/chart_data?cust_id=1&category_id=5&chart_type=1", map[string]any{
+		"cust_id":     1,
+		"category_id": 5,
+		"chart_type":  1,
+		"data":        []map[string]any{{"when": "2024-10-01", "value": 2400}, {"when": "2024-10-08", "value": 2500}},
+	})
+	server.AddResponse("/data/stats/member_recent_races?cust_id=1", map[string]any{
+		"cust_id": 1,
+		"races":   []map[string]any{{"subsession_id": 2001, "oldi_rating": 2400, "newi_rating": 2500}},
+	})
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	members, err := client.GetMembers(context.Background(), []int{1, 2}, true)
+	if err != nil {
+		t.Fatalf("client.GetMembers: %v", err)
+	}
+	if len(members.Members) != 2 || members.Members[0].Licenses[0].Irating != 2500 || members.Members[0].Licenses[0].SafetyRating != 3.45 {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+
+	chartData, err := client.GetMemberChartData(context.Background(), 1, 5, ChartTypeIRating)
+	if err != nil {
+		t.Fatalf("client.GetMemberChartData: %v", err)
+	}
+	if len(chartData.Data) != 2 || chartData.Data[1].Value != 2500 {
+		t.Fatalf("unexpected chart data: %+v", chartData)
+	}
+
+	recentRaces, err := client.GetMemberRecentRaces(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("client.GetMemberRecentRaces: %v", err)
+	}
+	if len(recentRaces.Races) != 1 || recentRaces.Races[0].NewiRating != 2500 {
+		t.Fatalf("unexpected recent races: %+v", recentRaces)
+	}
+}
@@ -0,0 +1,156 @@
+package irapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RateLimitBudget struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Known reports whether iRacing has already communicated its limits.
+func (b RateLimitBudget) Known() bool {
+	return b.Limit > 0
+}
+
+// RateLimiter is a token bucket shared by every call made through a client.
+// The bucket is sized and refilled from the X-RateLimit-* headers of the responses,
+// so the goroutines wait for the reset before iRacing has to answer 429.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	limit     int
+	remaining int
+	reset     time.Time
+
+	// Calls started but not yet reflected in the headers
+	inFlight int
+
+	// Set when iRacing answers 429 anyway
+	blockedUntil time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+// Budget returns a snapshot of the calls available until the next reset.
+func (l *RateLimiter) Budget() RateLimitBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+
+	return RateLimitBudget{
+		Limit:     l.limit,
+		Remaining: l.remaining,
+		Reset:     l.reset,
+	}
+}
+
+// Wait takes a token, waiting for the reset if the budget is exhausted.
+// With failFast it returns a RateLimitError instead of waiting.
+func (l *RateLimiter) Wait(ctx context.Context, path string, failFast bool) error {
+	for {
+		until, ok := l.take()
+		if ok {
+			return nil
+		}
+
+		if failFast {
+			return newRateLimitError(path, until)
+		}
+
+		if err := sleep(ctx, time.Until(until)); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *RateLimiter) take() (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+
+	if l.blockedUntil.After(now) {
+		return l.blockedUntil, false
+	}
+
+	// Until the first response the limits are unknown
+	if l.limit > 0 && l.remaining <= 0 {
+		if l.reset.IsZero() {
+			// Refilled, but the calls in flight took the whole budget: wait for their headers
+			return now.Add(time.Second), false
+		}
+		return l.reset, false
+	}
+
+	l.remaining--
+	l.inFlight++
+	return time.Time{}, true
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if l.limit > 0 && !l.reset.IsZero() && !l.reset.After(now) {
+		l.remaining = l.limit - l.inFlight
+		l.reset = time.Time{}
+	}
+}
+
+// Done releases the token taken by Wait and updates the bucket with the headers of the response, if any.
+func (l *RateLimiter) Done(header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight > 0 {
+		l.inFlight--
+	}
+
+	if header == nil {
+		return
+	}
+
+	limit, errLimit := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if errLimit != nil || errRemaining != nil || errReset != nil {
+		return
+	}
+
+	l.limit = limit
+	l.reset = time.Unix(reset, 0)
+
+	// The calls still running are not counted by iRacing yet
+	l.remaining = max(remaining-l.inFlight, 0)
+}
+
+// Block stops every call until the given time, after iRacing answered 429.
+func (l *RateLimiter) Block(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+	l.remaining = 0
+}
+
+// WithRateLimiter shares a limiter between several clients using the same account.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.limiter = limiter
+	}
+}
+
+// RateLimitBudget returns the calls available until the next reset, e.g. to size a pool of workers.
+func (c *IRacingApiClient) RateLimitBudget() RateLimitBudget {
+	return c.limiter.Budget()
+}
@@ -1,313 +1,231 @@
 package irapi
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 )
 
-type resultsResponse struct {
-	SubsessionId            int   `json:"subsession_id"`
-	AssociatedSubsessionIds []int `json:"associated_subsession_ids"`
-	CanProtest              bool  `json:"can_protest"`
-	CarClasses              []struct {
-		CarClassId      int    `json:"car_class_id"`
-		ShortName       string `json:"short_name"`
-		Name            string `json:"name"`
-		StrengthOfField int    `json:"strength_of_field"`
-		NumEntries      int    `json:"num_entries"`
-		CarsInClass     []struct {
-			CarId int `json:"car_id"`
-		} `json:"cars_in_class"`
-	} `json:"car_classes"`
-	CautionType           int    `json:"caution_type"`
-	CooldownMinutes       int    `json:"cooldown_minutes"`
-	CornersPerLap         int    `json:"corners_per_lap"`
-	DamageModel           int    `json:"damage_model"`
-	DriverChangeParam1    int    `json:"driver_change_param1"`
-	DriverChangeParam2    int    `json:"driver_change_param2"`
-	DriverChangeRule      int    `json:"driver_change_rule"`
-	DriverChanges         bool   `json:"driver_changes"`
-	EndTime               string `json:"end_time"`
-	EventAverageLap       int    `json:"event_average_lap"`
-	EventBestLapTime      int    `json:"event_best_lap_time"`
-	EventLapsComplete     int    `json:"event_laps_complete"`
-	EventStrengthOfField  int    `json:"event_strength_of_field"`
-	EventType             int    `json:"event_type"`
-	EventTypeName         string `json:"event_type_name"`
-	HeatInfoId            int    `json:"heat_info_id"`
-	HostId                int    `json:"host_id"`
-	LeagueId              int    `json:"league_id"`
-	LeagueName            string `json:"league_name"`
-	LeagueSeasonId        int    `json:"league_season_id"`
-	LicenseCategory       string `json:"license_category"`
-	LicenseCategoryId     int    `json:"license_category_id"`
-	LimitMinutes          int    `json:"limit_minutes"`
-	MaxTeamDrivers        int    `json:"max_team_drivers"`
-	MaxWeeks              int    `json:"max_weeks"`
-	MinTeamDrivers        int    `json:"min_team_drivers"`
-	NumCautionLaps        int    `json:"num_caution_laps"`
-	NumCautions           int    `json:"num_cautions"`
-	NumDrivers            int    `json:"num_drivers"`
-	NumLapsForQualAverage int    `json:"num_laps_for_qual_average"`
-	NumLapsForSoloAverage int    `json:"num_laps_for_solo_average"`
-	NumLeadChanges        int    `json:"num_lead_changes"`
-	OfficialSession       bool   `json:"official_session"`
-	PointsType            string `json:"points_type"`
-	PrivateSessionId      int    `json:"private_session_id"`
-	RaceWeekNum           int    `json:"race_week_num"`
-	RestrictResults       bool   `json:"restrict_results"`
-	ResultsRestricted     bool   `json:"results_restricted"`
-	SeasonId              int    `json:"season_id"`
-	SeasonName            string `json:"season_name"`
-	SeasonQuarter         int    `json:"season_quarter"`
-	SeasonShortName       string `json:"season_short_name"`
-	SeasonYear            int    `json:"season_year"`
-	SeriesId              int    `json:"series_id"`
-	SeriesName            string `json:"series_name"`
-	SeriesShortName       string `json:"series_short_name"`
-	SessionId             int    `json:"session_id"`
-	SessionName           string `json:"session_name"`
-	SessionResults        []struct {
-		SimsessionNumber   int    `json:"simsession_number"`
-		SimsessionName     string `json:"simsession_name"`
-		SimsessionType     int    `json:"simsession_type"`
-		SimsessionTypeName string `json:"simsession_type_name"`
-		SimsessionSubtype  int    `json:"simsession_subtype"`
-		Results            []struct {
-			CustId                int    `json:"cust_id"`
-			DisplayName           string `json:"display_name"`
-			AggregateChampPoints  int    `json:"aggregate_champ_points"`
-			Ai                    bool   `json:"ai"`
-			AverageLap            int    `json:"average_lap"`
-			BestLapNum            int    `json:"best_lap_num"`
-			BestLapTime           int    `json:"best_lap_time"`
-			BestNlapsNum          int    `json:"best_nlaps_num"`
-			BestNlapsTime         int    `json:"best_nlaps_time"`
-			BestQualLapAt         string `json:"best_qual_lap_at"`
-			BestQualLapNum        int    `json:"best_qual_lap_num"`
-			BestQualLapTime       int    `json:"best_qual_lap_time"`
-			CarClassId            int    `json:"car_class_id"`
-			CarClassName          string `json:"car_class_name"`
-			CarClassShortName     string `json:"car_class_short_name"`
-			CarId                 int    `json:"car_id"`
-			CarName               string `json:"car_name"`
-			ChampPoints           int    `json:"champ_points"`
-			ClassInterval         int    `json:"class_interval"`
-			ClubId                int    `json:"club_id"`
-			ClubName              string `json:"club_name"`
-			ClubPoints            int    `json:"club_points"`
-			ClubShortname         string `json:"club_shortname"`
-			CountryCode           string `json:"country_code"`
-			Division              int    `json:"division"`
-			DropRace              bool   `json:"drop_race"`
-			FinishPosition        int    `json:"finish_position"`
-			FinishPositionInClass int    `json:"finish_position_in_class"`
-			Friend                bool   `json:"friend"`
-			Helmet                struct {
-				Pattern    int    `json:"pattern"`
-				Color1     string `json:"color1"`
-				Color2     string `json:"color2"`
-				Color3     string `json:"color3"`
-				FaceType   int    `json:"face_type"`
-				HelmetType int    `json:"helmet_type"`
-			} `json:"helmet"`
-			Incidents         int `json:"incidents"`
-			Interval          int `json:"interval"`
-			LapsComplete      int `json:"laps_complete"`
-			LapsLead          int `json:"laps_lead"`
-			LeagueAggPoints   int `json:"league_agg_points"`
-			LeaguePoints      int `json:"league_points"`
-			LicenseChangeOval int `json:"license_change_oval"`
-			LicenseChangeRoad int `json:"license_change_road"`
-			Livery            struct {
-				CarId        int    `json:"car_id"`
-				Pattern      int    `json:"pattern"`
-				Color1       string `json:"color1"`
-				Color2       string `json:"color2"`
-				Color3       string `json:"color3"`
-				NumberFont   int    `json:"number_font"`
-				NumberColor1 string `json:"number_color1"`
-				NumberColor2 string `json:"number_color2"`
-				NumberColor3 string `json:"number_color3"`
-				NumberSlant  int    `json:"number_slant"`
-				Sponsor1     int    `json:"sponsor1"`
-				Sponsor2     int    `json:"sponsor2"`
-				CarNumber    string `json:"car_number"`
-				WheelColor   string `json:"wheel_color"`
-				RimType      int    `json:"rim_type"`
-			} `json:"livery"`
-			MaxPctFuelFill          int     `json:"max_pct_fuel_fill"`
-			Multiplier              int     `json:"multiplier"`
-			NewCpi                  float32 `json:"new_cpi"`
-			NewLicenseLevel         int     `json:"new_license_level"`
-			NewSubLevel             int     `json:"new_sub_level"`
-			NewTtrating             int     `json:"new_ttrating"`
-			NewiRating              int     `json:"newi_rating"`
-			OldCpi                  float32 `json:"old_cpi"`
-			OldLicenseLevel         int     `json:"old_license_level"`
-			OldSubLevel             int     `json:"old_sub_level"`
-			OldTtrating             int     `json:"old_ttrating"`
-			OldiRating              int     `json:"oldi_rating"`
-			OptLapsComplete         int     `json:"opt_laps_complete"`
-			Position                int     `json:"position"`
-			QualLapTime             int     `json:"qual_lap_time"`
-			ReasonOut               string  `json:"reason_out"`
-			ReasonOutId             int     `json:"reason_out_id"`
-			StartingPosition        int     `json:"starting_position"`
-			StartingPositionInClass int     `json:"starting_position_in_class"`
-			Suit                    struct {
-				Pattern int    `json:"pattern"`
-				Color1  string `json:"color1"`
-				Color2  string `json:"color2"`
-				Color3  string `json:"color3"`
-			} `json:"suit"`
-			Watched         bool `json:"watched"`
-			WeightPenaltyKg int  `json:"weight_penalty_kg"`
-		} `json:"results"`
-	} `json:"session_results"`
-	SessionSplits []struct {
-		SubsessionId         int `json:"subsession_id"`
-		EventStrengthOfField int `json:"event_strength_of_field"`
-	} `json:"session_splits"`
-	SpecialEventType int    `json:"special_event_type"`
-	StartTime        string `json:"start_time"`
-	Track            struct {
-		Category   string `json:"category"`
-		CategoryId int    `json:"category_id"`
-		ConfigName string `json:"config_name"`
-		TrackId    int    `json:"track_id"`
-		TrackName  string `json:"track_name"`
-	} `json:"track"`
-	TrackState struct {
-		LeaveMarbles         bool `json:"leave_marbles"`
-		PracticeGripCompound int  `json:"practice_grip_compound"`
-		PracticeRubber       int  `json:"practice_rubber"`
-		QualifyGripCompound  int  `json:"qualify_grip_compound"`
-		QualifyRubber        int  `json:"qualify_rubber"`
-		RaceGripCompound     int  `json:"race_grip_compound"`
-		RaceRubber           int  `json:"race_rubber"`
-		WarmupGripCompound   int  `json:"warmup_grip_compound"`
-		WarmupRubber         int  `json:"warmup_rubber"`
-	} `json:"track_state"`
-	Weather struct {
-		AllowFog                      bool   `json:"allow_fog"`
-		Fog                           int    `json:"fog"`
-		PrecipMm2hrBeforeFinalSession int    `json:"precip_mm2hr_before_final_session"`
-		PrecipMmFinalSession          int    `json:"precip_mm_final_session"`
-		PrecipOption                  int    `json:"precip_option"`
-		PrecipTimePct                 int    `json:"precip_time_pct"`
-		RelHumidity                   int    `json:"rel_humidity"`
-		SimulatedStartTime            string `json:"simulated_start_time"`
-		Skies                         int    `json:"skies"`
-		TempUnits                     int    `json:"temp_units"`
-		TempValue                     int    `json:"temp_value"`
-		TimeOfDay                     int    `json:"time_of_day"`
-		TrackWater                    int    `json:"track_water"`
-		Type                          int    `json:"type"`
-		Version                       int    `json:"version"`
-		WeatherVarInitial             int    `json:"weather_var_initial"`
-		WeatherVarOngoing             int    `json:"weather_var_ongoing"`
-		WindDir                       int    `json:"wind_dir"`
-		WindUnits                     int    `json:"wind_units"`
-		WindValue                     int    `json:"wind_value"`
-	} `json:"weather"`
+type ResultsResponse struct {
+	SubsessionId            int               `json:"subsession_id"`
+	AssociatedSubsessionIds []int             `json:"associated_subsession_ids"`
+	CanProtest              bool              `json:"can_protest"`
+	CarClasses              []ResultsCarClass `json:"car_classes"`
+	CautionType             int               `json:"caution_type"`
+	CooldownMinutes         int               `json:"cooldown_minutes"`
+	CornersPerLap           int               `json:"corners_per_lap"`
+	DamageModel             int               `json:"damage_model"`
+	DriverChangeParam1      int               `json:"driver_change_param1"`
+	DriverChangeParam2      int               `json:"driver_change_param2"`
+	DriverChangeRule        int               `json:"driver_change_rule"`
+	DriverChanges           bool              `json:"driver_changes"`
+	EndTime                 string            `json:"end_time"`
+	EventAverageLap         int               `json:"event_average_lap"`
+	EventBestLapTime        int               `json:"event_best_lap_time"`
+	EventLapsComplete       int               `json:"event_laps_complete"`
+	EventStrengthOfField    int               `json:"event_strength_of_field"`
+	EventType               int               `json:"event_type"`
+	EventTypeName           string            `json:"event_type_name"`
+	HeatInfoId              int               `json:"heat_info_id"`
+	HostId                  int               `json:"host_id"`
+	LeagueId                int               `json:"league_id"`
+	LeagueName              string            `json:"league_name"`
+	LeagueSeasonId          int               `json:"league_season_id"`
+	LicenseCategory         string            `json:"license_category"`
+	LicenseCategoryId       int               `json:"license_category_id"`
+	LimitMinutes            int               `json:"limit_minutes"`
+	MaxTeamDrivers          int               `json:"max_team_drivers"`
+	MaxWeeks                int               `json:"max_weeks"`
+	MinTeamDrivers          int               `json:"min_team_drivers"`
+	NumCautionLaps          int               `json:"num_caution_laps"`
+	NumCautions             int               `json:"num_cautions"`
+	NumDrivers              int               `json:"num_drivers"`
+	NumLapsForQualAverage   int               `json:"num_laps_for_qual_average"`
+	NumLapsForSoloAverage   int               `json:"num_laps_for_solo_average"`
+	NumLeadChanges          int               `json:"num_lead_changes"`
+	OfficialSession         bool              `json:"official_session"`
+	PointsType              string            `json:"points_type"`
+	PrivateSessionId        int               `json:"private_session_id"`
+	RaceWeekNum             int               `json:"race_week_num"`
+	RestrictResults         bool              `json:"restrict_results"`
+	ResultsRestricted       bool              `json:"results_restricted"`
+	SeasonId                int               `json:"season_id"`
+	SeasonName              string            `json:"season_name"`
+	SeasonQuarter           int               `json:"season_quarter"`
+	SeasonShortName         string            `json:"season_short_name"`
+	SeasonYear              int               `json:"season_year"`
+	SeriesId                int               `json:"series_id"`
+	SeriesName              string            `json:"series_name"`
+	SeriesShortName         string            `json:"series_short_name"`
+	SessionId               int               `json:"session_id"`
+	SessionName             string            `json:"session_name"`
+	SessionResults          []SessionResult   `json:"session_results"`
+	SessionSplits           []SessionSplit    `json:"session_splits"`
+	SpecialEventType        int               `json:"special_event_type"`
+	StartTime               string            `json:"start_time"`
+	Track                   TrackRef          `json:"track"`
+	TrackState              TrackState        `json:"track_state"`
+	Weather                 Weather           `json:"weather"`
+}
+
+type ResultsCarClass struct {
+	CarClassId      int      `json:"car_class_id"`
+	ShortName       string   `json:"short_name"`
+	Name            string   `json:"name"`
+	StrengthOfField int      `json:"strength_of_field"`
+	NumEntries      int      `json:"num_entries"`
+	CarsInClass     []CarRef `json:"cars_in_class"`
+}
+
+type SessionSplit struct {
+	SubsessionId         int `json:"subsession_id"`
+	EventStrengthOfField int `json:"event_strength_of_field"`
+}
+
+// SessionResult holds the results of a simsession (practice, qualify, race...)
+type SessionResult struct {
+	SimsessionNumber   int            `json:"simsession_number"`
+	SimsessionName     string         `json:"simsession_name"`
+	SimsessionType     SimsessionType `json:"simsession_type"`
+	SimsessionTypeName string         `json:"simsession_type_name"`
+	SimsessionSubtype  int            `json:"simsession_subtype"`
+	WeatherResult      *WeatherResult `json:"weather_result"`
+	Results            []DriverResult `json:"results"`
+}
+
+// DriverResult is a row of the results. In team events the row is the team, with TeamId set
+// and the results of its drivers in DriverResults.
+type DriverResult struct {
+	CustId                  int     `json:"cust_id"`
+	TeamId                  int     `json:"team_id"`
+	DisplayName             string  `json:"display_name"`
+	AggregateChampPoints    int     `json:"aggregate_champ_points"`
+	Ai                      bool    `json:"ai"`
+	AverageLap              int     `json:"average_lap"`
+	BestLapNum              int     `json:"best_lap_num"`
+	BestLapTime             int     `json:"best_lap_time"`
+	BestNlapsNum            int     `json:"best_nlaps_num"`
+	BestNlapsTime           int     `json:"best_nlaps_time"`
+	BestQualLapAt           string  `json:"best_qual_lap_at"`
+	BestQualLapNum          int     `json:"best_qual_lap_num"`
+	BestQualLapTime         int     `json:"best_qual_lap_time"`
+	CarClassId              int     `json:"car_class_id"`
+	CarClassName            string  `json:"car_class_name"`
+	CarClassShortName       string  `json:"car_class_short_name"`
+	CarId                   int     `json:"car_id"`
+	CarName                 string  `json:"car_name"`
+	ChampPoints             int     `json:"champ_points"`
+	ClassInterval           int     `json:"class_interval"`
+	ClubId                  int     `json:"club_id"`
+	ClubName                string  `json:"club_name"`
+	ClubPoints              int     `json:"club_points"`
+	ClubShortname           string  `json:"club_shortname"`
+	CountryCode             string  `json:"country_code"`
+	Division                int     `json:"division"`
+	DropRace                bool    `json:"drop_race"`
+	FinishPosition          int     `json:"finish_position"`
+	FinishPositionInClass   int     `json:"finish_position_in_class"`
+	Friend                  bool    `json:"friend"`
+	Helmet                  Helmet  `json:"helmet"`
+	Incidents               int     `json:"incidents"`
+	Interval                int     `json:"interval"`
+	LapsComplete            int     `json:"laps_complete"`
+	LapsLead                int     `json:"laps_lead"`
+	LeagueAggPoints         int     `json:"league_agg_points"`
+	LeaguePoints            int     `json:"league_points"`
+	LicenseChangeOval       int     `json:"license_change_oval"`
+	LicenseChangeRoad       int     `json:"license_change_road"`
+	Livery                  Livery  `json:"livery"`
+	MaxPctFuelFill          int     `json:"max_pct_fuel_fill"`
+	Multiplier              int     `json:"multiplier"`
+	NewCpi                  float32 `json:"new_cpi"`
+	NewLicenseLevel         int     `json:"new_license_level"`
+	NewSubLevel             int     `json:"new_sub_level"`
+	NewTtrating             int     `json:"new_ttrating"`
+	NewiRating              int     `json:"newi_rating"`
+	OldCpi                  float32 `json:"old_cpi"`
+	OldLicenseLevel         int     `json:"old_license_level"`
+	OldSubLevel             int     `json:"old_sub_level"`
+	OldTtrating             int     `json:"old_ttrating"`
+	OldiRating              int     `json:"oldi_rating"`
+	OptLapsComplete         int     `json:"opt_laps_complete"`
+	Position                int     `json:"position"`
+	QualLapTime             int     `json:"qual_lap_time"`
+	ReasonOut               string  `json:"reason_out"`
+	ReasonOutId             int     `json:"reason_out_id"`
+	StartingPosition        int     `json:"starting_position"`
+	StartingPositionInClass int     `json:"starting_position_in_class"`
+	Suit                    Suit    `json:"suit"`
+	Watched                 bool    `json:"watched"`
+	WeightPenaltyKg         int     `json:"weight_penalty_kg"`
+
+	DriverResults []DriverResult `json:"driver_results"`
+}
+
+func (r DriverResult) IsTeam() bool {
+	return r.TeamId != 0
+}
+
+// EntryId identifies the car in the lap data: the team for team events, else the driver.
+func (r DriverResult) EntryId() int {
+	if r.IsTeam() {
+		return r.TeamId
+	}
+	return r.CustId
 }
 
 type ResultsLapDataResponse struct {
-	Success     bool `json:"success"`
-	SessionInfo struct {
-		SubsessionId          int    `json:"subsession_id"`
-		SessionId             int    `json:"session_id"`
-		SimsessionNumber      int    `json:"simsession_number"`
-		SimsessionType        int    `json:"simsession_type"`
-		SimsessionName        string `json:"simsession_name"`
-		NumLapsForQualAverage int    `json:"num_laps_for_qual_average"`
-		NumLapsForSoloAverage int    `json:"num_laps_for_solo_average"`
-		EventType             int    `json:"event_type"`
-		EventTypeName         string `json:"event_type_name"`
-		PrivateSessionId      int    `json:"private_session_id"`
-		SeasonName            string `json:"season_name"`
-		SeasonShortName       string `json:"season_short_name"`
-		SeriesName            string `json:"series_name"`
-		SeriesShortName       string `json:"series_short_name"`
-		SessionName           string `json:"session_name"`
-		RestrictResults       bool   `json:"restrict_results"`
-		StartTime             string `json:"start_time"`
-		Track                 struct {
-			ConfigName string `json:"config_name"`
-			TrackId    int    `json:"track_id"`
-			TrackName  string `json:"track_name"`
-		} `json:"track"`
-	} `json:"session_info"`
-	BestLapNum      int              `json:"best_lap_num"`
-	BestLapTime     int              `json:"best_lap_time"`
-	BestNlapsNum    int              `json:"best_nlaps_num"`
-	BestNlapsTime   int              `json:"best_nlaps_time"`
-	BestQualLapNum  int              `json:"best_qual_lap_num"`
-	BestQualLapTime int              `json:"best_qual_lap_time"`
-	BestQualLapAt   string           `json:"best_qual_lap_at"`
-	ChunkInfo       IRacingChunkInfo `json:"chunk_info"`
-	LastUpdated     string           `json:"last_updated"`
-	GroupId         int              `json:"group_id"`
-	CustId          int              `json:"cust_id"`
-	Name            string           `json:"name"`
-	CarId           int              `json:"car_id"`
-	LicenseLevel    int              `json:"license_level"`
-	Livery          struct {
-		CarId        int    `json:"car_id"`
-		Pattern      int    `json:"pattern"`
-		Color1       string `json:"color1"`
-		Color2       string `json:"color2"`
-		Color3       string `json:"color3"`
-		NumberFont   int    `json:"number_font"`
-		NumberColor1 string `json:"number_color1"`
-		NumberColor2 string `json:"number_color2"`
-		NumberColor3 string `json:"number_color3"`
-		NumberSlant  int    `json:"number_slant"`
-		Sponsor1     int    `json:"sponsor1"`
-		Sponsor2     int    `json:"sponsor2"`
-		CarNumber    string `json:"car_number"`
-		WheelColor   string `json:"wheel_color"`
-		RimType      int    `json:"rim_type"`
-	} `json:"livery"`
-	Laps []ResultsLapDataChunk `json:"laps"` // From chunks
+	Success         bool                  `json:"success"`
+	SessionInfo     ResultsSessionInfo    `json:"session_info"`
+	BestLapNum      int                   `json:"best_lap_num"`
+	BestLapTime     int                   `json:"best_lap_time"`
+	BestNlapsNum    int                   `json:"best_nlaps_num"`
+	BestNlapsTime   int                   `json:"best_nlaps_time"`
+	BestQualLapNum  int                   `json:"best_qual_lap_num"`
+	BestQualLapTime int                   `json:"best_qual_lap_time"`
+	BestQualLapAt   string                `json:"best_qual_lap_at"`
+	ChunkInfo       IRacingChunkInfo      `json:"chunk_info"`
+	LastUpdated     string                `json:"last_updated"`
+	GroupId         int                   `json:"group_id"`
+	CustId          int                   `json:"cust_id"`
+	TeamId          int                   `json:"team_id"`
+	Name            string                `json:"name"`
+	CarId           int                   `json:"car_id"`
+	LicenseLevel    int                   `json:"license_level"`
+	Livery          Livery                `json:"livery"`
+	Laps            []ResultsLapDataChunk `json:"laps"` // From chunks
 }
 
+// ResultsLapDataChunk is a lap. In team events GroupId is the team and CustId the driver of the lap.
 type ResultsLapDataChunk struct {
-	GroupId          int    `json:"group_id"`
-	Name             string `json:"name"`
-	CustId           int    `json:"cust_id"`
-	DisplayName      string `json:"display_name"`
-	LapNumber        int    `json:"lap_number"`
-	Flags            int    `json:"flags"`
-	Incident         bool   `json:"incident"`
-	SessionTime      int    `json:"session_time"`
-	SessionStartTime int    `json:"session_start_time"`
-	LapTime          int    `json:"lap_time"`
-	TeamFastestLap   bool   `json:"team_fastest_lap"`
-	PersonalBestLap  bool   `json:"personal_best_lap"`
-	Helmet           struct {
-		Pattern    int    `json:"pattern"`
-		Color1     string `json:"color1"`
-		Color2     string `json:"color2"`
-		Color3     string `json:"color3"`
-		FaceType   int    `json:"face_type"`
-		HelmetType int    `json:"helmet_type"`
-	} `json:"helmet"`
-	LicenseLevel int      `json:"license_level"`
-	CarNumber    string   `json:"car_number"`
-	LapEvents    []string `json:"lap_events"`
-	Ai           bool     `json:"ai"`
+	GroupId          int      `json:"group_id"`
+	Name             string   `json:"name"`
+	CustId           int      `json:"cust_id"`
+	DisplayName      string   `json:"display_name"`
+	LapNumber        int      `json:"lap_number"`
+	Flags            LapFlag  `json:"flags"`
+	Incident         bool     `json:"incident"`
+	SessionTime      int      `json:"session_time"`
+	SessionStartTime int      `json:"session_start_time"`
+	LapTime          int      `json:"lap_time"`
+	TeamFastestLap   bool     `json:"team_fastest_lap"`
+	PersonalBestLap  bool     `json:"personal_best_lap"`
+	Helmet           Helmet   `json:"helmet"`
+	LicenseLevel     int      `json:"license_level"`
+	CarNumber        string   `json:"car_number"`
+	LapEvents        []string `json:"lap_events"`
+	Ai               bool     `json:"ai"`
 }
 
-func (client *IRacingApiClient) GetResults(subsessionId int) (*resultsResponse, error) {
+func (client *IRacingApiClient) GetResults(ctx context.Context, subsessionId int) (*ResultsResponse, error) {
 	url := "/data/results/get?subsession_id=" + strconv.Itoa(subsessionId)
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
-	response := &resultsResponse{}
+	response := &ResultsResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
 	if err != nil {
 		return nil, err
@@ -316,12 +234,68 @@ func (client *IRacingApiClient) GetResults(subsessionId int) (*resultsResponse,
 	return response, nil
 }
 
-func (client *IRacingApiClient) GetResultsLapData(subsessionId int, simsessionNumber int, custId int) (*ResultsLapDataResponse, error) {
+// GetResultsLapData returns the laps of a driver in a simsession, all in memory.
+func (client *IRacingApiClient) GetResultsLapData(ctx context.Context, subsessionId int, simsessionNumber int, custId int) (*ResultsLapDataResponse, error) {
+	laps := make([]ResultsLapDataChunk, 0)
+
+	response, err := client.IterResultsLapData(ctx, subsessionId, simsessionNumber, custId, func(lap ResultsLapDataChunk) error {
+		laps = append(laps, lap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Laps = laps
+
+	return response, nil
+}
+
+// IterResultsLapData streams the laps to fn in order, so long races don't need to fit in memory.
+// The returned response has no Laps. An error returned by fn stops the iteration.
+func (client *IRacingApiClient) IterResultsLapData(ctx context.Context, subsessionId int, simsessionNumber int, custId int, fn func(ResultsLapDataChunk) error) (*ResultsLapDataResponse, error) {
 	url := "/data/results/lap_data?subsession_id=" + strconv.Itoa(subsessionId) + "&simsession_number=" + strconv.Itoa(simsessionNumber) + "&cust_id=" + strconv.Itoa(custId)
-	respBody, err := client.get(url)
+	return client.iterResultsLapData(ctx, url, fn)
+}
+
+// GetResultsLapDataByTeam returns the laps of a team in a simsession, all in memory.
+func (client *IRacingApiClient) GetResultsLapDataByTeam(ctx context.Context, subsessionId int, simsessionNumber int, teamId int) (*ResultsLapDataResponse, error) {
+	laps := make([]ResultsLapDataChunk, 0)
+
+	response, err := client.IterResultsLapDataByTeam(ctx, subsessionId, simsessionNumber, teamId, func(lap ResultsLapDataChunk) error {
+		laps = append(laps, lap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Laps = laps
+
+	return response, nil
+}
+
+// IterResultsLapDataByTeam is IterResultsLapData for team events, where the laps of all the drivers
+// of a team are requested at once.
+func (client *IRacingApiClient) IterResultsLapDataByTeam(ctx context.Context, subsessionId int, simsessionNumber int, teamId int, fn func(ResultsLapDataChunk) error) (*ResultsLapDataResponse, error) {
+	url := "/data/results/lap_data?subsession_id=" + strconv.Itoa(subsessionId) + "&simsession_number=" + strconv.Itoa(simsessionNumber) + "&team_id=" + strconv.Itoa(teamId)
+	return client.iterResultsLapData(ctx, url, fn)
+}
+
+// IterResultsLapDataByEntry gets the laps of a row of the results, by team or by driver.
+func (client *IRacingApiClient) IterResultsLapDataByEntry(ctx context.Context, subsessionId int, simsessionNumber int, result DriverResult, fn func(ResultsLapDataChunk) error) (*ResultsLapDataResponse, error) {
+	if result.IsTeam() {
+		return client.IterResultsLapDataByTeam(ctx, subsessionId, simsessionNumber, result.TeamId, fn)
+	}
+	return client.IterResultsLapData(ctx, subsessionId, simsessionNumber, result.CustId, fn)
+}
+
+func (client *IRacingApiClient) iterResultsLapData(ctx context.Context, url string, fn func(ResultsLapDataChunk) error) (*ResultsLapDataResponse, error) {
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := &ResultsLapDataResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
@@ -329,19 +303,150 @@ func (client *IRacingApiClient) GetResultsLapData(subsessionId int, simsessionNu
 		return nil, err
 	}
 
-	chunksData, err := client.getChunks(&response.ChunkInfo)
+	err = iterChunks(ctx, client, &response.ChunkInfo, fn)
 	if err != nil {
 		return nil, err
 	}
 
-	response.Laps = make([]ResultsLapDataChunk, 0)
-	for _, chunk := range chunksData {
-		chunkLaps := make([]ResultsLapDataChunk, 0)
-		err := json.NewDecoder(chunk).Decode(&chunkLaps)
-		if err != nil {
-			return nil, err
-		}
-		response.Laps = append(response.Laps, chunkLaps...)
+	return response, nil
+}
+
+type ResultsEventLogResponse struct {
+	Success     bool                   `json:"success"`
+	SessionInfo ResultsSessionInfo     `json:"session_info"`
+	ChunkInfo   IRacingChunkInfo       `json:"chunk_info"`
+	Events      []ResultsEventLogEntry `json:"events"` // From chunks
+}
+
+// ResultsEventLogEntry is a race control message: cautions, penalties, driver swaps, chat...
+type ResultsEventLogEntry struct {
+	SubsessionId     int    `json:"subsession_id"`
+	SimsessionNumber int    `json:"simsession_number"`
+	SessionTime      int    `json:"session_time"`
+	EventSeq         int    `json:"event_seq"`
+	EventCode        int    `json:"event_code"`
+	GroupId          int    `json:"group_id"`
+	CustId           int    `json:"cust_id"`
+	DisplayName      string `json:"display_name"`
+	LapNumber        int    `json:"lap_number"`
+	Description      string `json:"description"`
+	Message          string `json:"message"`
+}
+
+// GetResultsEventLog returns the race control timeline of a simsession, all in memory.
+func (client *IRacingApiClient) GetResultsEventLog(ctx context.Context, subsessionId int, simsessionNumber int) (*ResultsEventLogResponse, error) {
+	events := make([]ResultsEventLogEntry, 0)
+
+	response, err := client.IterResultsEventLog(ctx, subsessionId, simsessionNumber, func(event ResultsEventLogEntry) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Events = events
+
+	return response, nil
+}
+
+// IterResultsEventLog streams the events to fn in order. The returned response has no Events.
+func (client *IRacingApiClient) IterResultsEventLog(ctx context.Context, subsessionId int, simsessionNumber int, fn func(ResultsEventLogEntry) error) (*ResultsEventLogResponse, error) {
+	url := "/data/results/event_log?subsession_id=" + strconv.Itoa(subsessionId) + "&simsession_number=" + strconv.Itoa(simsessionNumber)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &ResultsEventLogResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	err = iterChunks(ctx, client, &response.ChunkInfo, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type ResultsLapChartDataResponse struct {
+	Success         bool                   `json:"success"`
+	SessionInfo     ResultsSessionInfo     `json:"session_info"`
+	BestLapNum      int                    `json:"best_lap_num"`
+	BestLapTime     int                    `json:"best_lap_time"`
+	BestNlapsNum    int                    `json:"best_nlaps_num"`
+	BestNlapsTime   int                    `json:"best_nlaps_time"`
+	BestQualLapNum  int                    `json:"best_qual_lap_num"`
+	BestQualLapTime int                    `json:"best_qual_lap_time"`
+	BestQualLapAt   string                 `json:"best_qual_lap_at"`
+	ChunkInfo       IRacingChunkInfo       `json:"chunk_info"`
+	LastUpdated     string                 `json:"last_updated"`
+	Laps            []ResultsLapChartEntry `json:"laps"` // From chunks
+}
+
+// ResultsLapChartEntry is a lap of a car, with its position at the end of the lap.
+type ResultsLapChartEntry struct {
+	GroupId          int      `json:"group_id"`
+	Name             string   `json:"name"`
+	CustId           int      `json:"cust_id"`
+	DisplayName      string   `json:"display_name"`
+	LapNumber        int      `json:"lap_number"`
+	Flags            LapFlag  `json:"flags"`
+	Incident         bool     `json:"incident"`
+	SessionTime      int      `json:"session_time"`
+	SessionStartTime int      `json:"session_start_time"`
+	LapTime          int      `json:"lap_time"`
+	TeamFastestLap   bool     `json:"team_fastest_lap"`
+	PersonalBestLap  bool     `json:"personal_best_lap"`
+	LicenseLevel     int      `json:"license_level"`
+	CarNumber        string   `json:"car_number"`
+	LapEvents        []string `json:"lap_events"`
+	LapPosition      int      `json:"lap_position"`
+	Interval         int      `json:"interval"`
+	IntervalUnits    string   `json:"interval_units"`
+	FastestLap       bool     `json:"fastest_lap"`
+	Ai               bool     `json:"ai"`
+}
+
+// GetResultsLapChartData returns the laps of every car in a simsession, all in memory.
+func (client *IRacingApiClient) GetResultsLapChartData(ctx context.Context, subsessionId int, simsessionNumber int) (*ResultsLapChartDataResponse, error) {
+	laps := make([]ResultsLapChartEntry, 0)
+
+	response, err := client.IterResultsLapChartData(ctx, subsessionId, simsessionNumber, func(lap ResultsLapChartEntry) error {
+		laps = append(laps, lap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Laps = laps
+
+	return response, nil
+}
+
+// IterResultsLapChartData streams the laps of every car to fn, in order. The returned response has no Laps.
+func (client *IRacingApiClient) IterResultsLapChartData(ctx context.Context, subsessionId int, simsessionNumber int, fn func(ResultsLapChartEntry) error) (*ResultsLapChartDataResponse, error) {
+	url := "/data/results/lap_chart_data?subsession_id=" + strconv.Itoa(subsessionId) + "&simsession_number=" + strconv.Itoa(simsessionNumber)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &ResultsLapChartDataResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	err = iterChunks(ctx, client, &response.ChunkInfo, fn)
+	if err != nil {
+		return nil, err
 	}
 
 	return response, nil
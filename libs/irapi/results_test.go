@@ -0,0 +1,112 @@
+package irapi
+
+import (
+	"context"
+	"testing"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
+)
+
+func TestResultsEventLog(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/results/event_log?subsession_id=1&simsession_number=0",
+		map[string]any{"success": true, "session_info": map[string]any{"subsession_id": 1}, "chunk_info": map[string]any{}},
+		[]map[string]any{{"event_seq": 1, "event_code": 12, "description": "Caution"}},
+		[]map[string]any{{"event_seq": 2, "event_code": 5, "cust_id": 10, "description": "Penalty", "message": "Drive through"}},
+	)
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	eventLog, err := client.GetResultsEventLog(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("client.GetResultsEventLog: %v", err)
+	}
+	if eventLog.SessionInfo.SubsessionId != 1 || len(eventLog.Events) != 2 || eventLog.Events[1].Message != "Drive through" {
+		t.Fatalf("unexpected event log: %+v", eventLog)
+	}
+}
+
+func TestResultsLapChartData(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/results/lap_chart_data?subsession_id=1&simsession_number=0",
+		map[string]any{"success": true, "best_lap_num": 2, "chunk_info": map[string]any{}},
+		[]map[string]any{{"cust_id": 10, "lap_number": 1, "lap_position": 1}, {"cust_id": 20, "lap_number": 1, "lap_position": 2}},
+		[]map[string]any{{"cust_id": 20, "lap_number": 2, "lap_position": 1}, {"cust_id": 10, "lap_number": 2, "lap_position": 2}},
+	)
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	lapChart, err := client.GetResultsLapChartData(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("client.GetResultsLapChartData: %v", err)
+	}
+	if lapChart.BestLapNum != 2 || len(lapChart.Laps) != 4 || lapChart.Laps[2].CustId != 20 || lapChart.Laps[2].LapPosition != 1 {
+		t.Fatalf("unexpected lap chart: %+v", lapChart)
+	}
+}
+
+func TestResultsTeamLapData(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	server.AddResponse("/data/results/get?subsession_id=1", map[string]any{
+		"subsession_id": 1,
+		"session_results": []map[string]any{{
+			"simsession_number": 0,
+			"simsession_type":   6,
+			"results": []map[string]any{{
+				"team_id":      -100,
+				"display_name": "Team",
+				"car_id":       5,
+				"driver_results": []map[string]any{
+					{"cust_id": 10, "team_id": -100, "laps_complete": 2},
+					{"cust_id": 20, "team_id": -100, "laps_complete": 1},
+				},
+			}},
+		}},
+	})
+	server.AddResponse("/data/results/lap_data?subsession_id=1&simsession_number=0&team_id=-100",
+		map[string]any{"success": true, "team_id": -100, "chunk_info": map[string]any{}},
+		[]map[string]any{
+			{"group_id": -100, "cust_id": 10, "lap_number": 1},
+			{"group_id": -100, "cust_id": 10, "lap_number": 2},
+			{"group_id": -100, "cust_id": 20, "lap_number": 3},
+		},
+	)
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	results, err := client.GetResults(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("client.GetResults: %v", err)
+	}
+	team := results.SessionResults[0].Results[0]
+	if !team.IsTeam() || team.EntryId() != -100 || len(team.DriverResults) != 2 || team.DriverResults[1].CustId != 20 {
+		t.Fatalf("unexpected team result: %+v", team)
+	}
+
+	drivers := make([]int, 0)
+	_, err = client.IterResultsLapDataByEntry(context.Background(), 1, 0, team, func(lap ResultsLapDataChunk) error {
+		drivers = append(drivers, lap.CustId)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("client.IterResultsLapDataByEntry: %v", err)
+	}
+	if len(drivers) != 3 || drivers[0] != 10 || drivers[2] != 20 {
+		t.Fatalf("unexpected drivers of the laps: %v", drivers)
+	}
+}
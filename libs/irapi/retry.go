@@ -0,0 +1,62 @@
+package irapi
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	// Total number of attempts for a single call, including the first one. Zero or less means no limit.
+	MaxAttempts int
+
+	// Exponential backoff (with full jitter) applied to server errors, network errors and timeouts
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Return a RateLimitError instead of sleeping until the rate limit resets
+	FailOnRateLimit bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *IRacingApiClient) {
+		c.retryPolicy = policy
+	}
+}
+
+func (p RetryPolicy) canRetry(attempt int) bool {
+	return p.MaxAttempts <= 0 || attempt < p.MaxAttempts
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay + 1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
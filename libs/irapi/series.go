@@ -0,0 +1,310 @@
+package irapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchSeriesMaxWindow is the widest start range accepted by /data/results/search_series.
+const SearchSeriesMaxWindow = 90 * 24 * time.Hour
+
+type SeriesResponse struct {
+	AllowedLicenses []struct {
+		GroupName       string `json:"group_name"`
+		LicenseGroup    int    `json:"license_group"`
+		MaxLicenseLevel int    `json:"max_license_level"`
+		MinLicenseLevel int    `json:"min_license_level"`
+	} `json:"allowed_licenses"`
+	Category        string `json:"category"`
+	CategoryId      int    `json:"category_id"`
+	Eligible        bool   `json:"eligible"`
+	ForumUrl        string `json:"forum_url"`
+	MaxStarters     int    `json:"max_starters"`
+	MinStarters     int    `json:"min_starters"`
+	OvalCautionType int    `json:"oval_caution_type"`
+	RoadCautionType int    `json:"road_caution_type"`
+	SearchFilters   string `json:"search_filters"`
+	SeriesId        int    `json:"series_id"`
+	SeriesName      string `json:"series_name"`
+	SeriesShortName string `json:"series_short_name"`
+}
+
+type SeriesSeasonResponse struct {
+	SeasonId            int    `json:"season_id"`
+	SeasonName          string `json:"season_name"`
+	SeasonShortName     string `json:"season_short_name"`
+	SeasonYear          int    `json:"season_year"`
+	SeasonQuarter       int    `json:"season_quarter"`
+	SeriesId            int    `json:"series_id"`
+	Active              bool   `json:"active"`
+	Official            bool   `json:"official"`
+	Complete            bool   `json:"complete"`
+	Fixed               bool   `json:"fixed"`
+	Multiclass          bool   `json:"multiclass"`
+	DriverChanges       bool   `json:"driver_changes"`
+	MaxWeeks            int    `json:"max_weeks"`
+	RaceWeek            int    `json:"race_week"`
+	StartDate           string `json:"start_date"`
+	LicenseGroup        int    `json:"license_group"`
+	ScheduleDescription string `json:"schedule_description"`
+	CarClassIds         []int  `json:"car_class_ids"`
+	Schedules           []struct {
+		SeasonId      int      `json:"season_id"`
+		RaceWeekNum   int      `json:"race_week_num"`
+		SeriesId      int      `json:"series_id"`
+		SeriesName    string   `json:"series_name"`
+		SeasonName    string   `json:"season_name"`
+		ScheduleName  string   `json:"schedule_name"`
+		StartDate     string   `json:"start_date"`
+		RaceLapLimit  int      `json:"race_lap_limit"`
+		RaceTimeLimit int      `json:"race_time_limit"`
+		StartType     string   `json:"start_type"`
+		RestartType   string   `json:"restart_type"`
+		CategoryId    int      `json:"category_id"`
+		Category      string   `json:"category"`
+		Track         TrackRef `json:"track"`
+	} `json:"schedules"`
+}
+
+type RaceGuideResponse struct {
+	Subscribed     bool   `json:"subscribed"`
+	Success        bool   `json:"success"`
+	BlockBeginTime string `json:"block_begin_time"`
+	BlockEndTime   string `json:"block_end_time"`
+	Sessions       []struct {
+		SeasonId     int    `json:"season_id"`
+		SeriesId     int    `json:"series_id"`
+		RaceWeekNum  int    `json:"race_week_num"`
+		SessionId    int    `json:"session_id"`
+		StartTime    string `json:"start_time"`
+		EndTime      string `json:"end_time"`
+		EntryCount   int    `json:"entry_count"`
+		SuperSession bool   `json:"super_session"`
+	} `json:"sessions"`
+}
+
+type SearchSeriesParams struct {
+	// Either the season or the start range is required. The start range is split in windows
+	// of SearchSeriesMaxWindow; a zero StartRangeEnd means now.
+	SeasonYear      int
+	SeasonQuarter   int
+	StartRangeBegin time.Time
+	StartRangeEnd   time.Time
+
+	SeriesId     int
+	CustId       int
+	TeamId       int
+	OfficialOnly bool
+	EventTypes   []int
+	CategoryIds  []int
+}
+
+type SearchSeriesResult struct {
+	SessionId            int      `json:"session_id"`
+	SubsessionId         int      `json:"subsession_id"`
+	StartTime            string   `json:"start_time"`
+	EndTime              string   `json:"end_time"`
+	LicenseCategoryId    int      `json:"license_category_id"`
+	LicenseCategory      string   `json:"license_category"`
+	NumDrivers           int      `json:"num_drivers"`
+	NumCautions          int      `json:"num_cautions"`
+	NumCautionLaps       int      `json:"num_caution_laps"`
+	NumLeadChanges       int      `json:"num_lead_changes"`
+	EventLapsComplete    int      `json:"event_laps_complete"`
+	DriverChanges        bool     `json:"driver_changes"`
+	WinnerGroupId        int      `json:"winner_group_id"`
+	WinnerName           string   `json:"winner_name"`
+	WinnerAi             bool     `json:"winner_ai"`
+	OfficialSession      bool     `json:"official_session"`
+	SeasonId             int      `json:"season_id"`
+	SeasonYear           int      `json:"season_year"`
+	SeasonQuarter        int      `json:"season_quarter"`
+	EventType            int      `json:"event_type"`
+	EventTypeName        string   `json:"event_type_name"`
+	SeriesId             int      `json:"series_id"`
+	SeriesName           string   `json:"series_name"`
+	SeriesShortName      string   `json:"series_short_name"`
+	RaceWeekNum          int      `json:"race_week_num"`
+	EventStrengthOfField int      `json:"event_strength_of_field"`
+	EventAverageLap      int      `json:"event_average_lap"`
+	EventBestLapTime     int      `json:"event_best_lap_time"`
+	Track                TrackRef `json:"track"`
+}
+
+type searchSeriesResponse struct {
+	Type string `json:"type"`
+	Data struct {
+		Success   bool             `json:"success"`
+		ChunkInfo IRacingChunkInfo `json:"chunk_info"`
+	} `json:"data"`
+}
+
+func (client *IRacingApiClient) GetSeries(ctx context.Context) (*[]SeriesResponse, error) {
+	url := "/data/series/get"
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &[]SeriesResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (client *IRacingApiClient) GetSeriesSeasons(ctx context.Context, includeSeries bool) (*[]SeriesSeasonResponse, error) {
+	url := "/data/series/seasons?include_series=" + strconv.FormatBool(includeSeries)
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &[]SeriesSeasonResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetRaceGuide returns the official sessions starting from the given time, or from now if zero.
+func (client *IRacingApiClient) GetRaceGuide(ctx context.Context, from time.Time, includeEndAfterFrom bool) (*RaceGuideResponse, error) {
+	url := "/data/season/race_guide?include_end_after_from=" + strconv.FormatBool(includeEndAfterFrom)
+	if !from.IsZero() {
+		url += "&from=" + formatSearchTime(from)
+	}
+
+	respBody, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	response := &RaceGuideResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// SearchSeries returns all the official sessions matching the params, in memory.
+func (client *IRacingApiClient) SearchSeries(ctx context.Context, params SearchSeriesParams) ([]SearchSeriesResult, error) {
+	results := make([]SearchSeriesResult, 0)
+
+	err := client.IterSearchSeries(ctx, params, func(result SearchSeriesResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// IterSearchSeries streams the sessions matching the params to fn, one window of the start range at a time.
+// An error returned by fn stops the iteration.
+func (client *IRacingApiClient) IterSearchSeries(ctx context.Context, params SearchSeriesParams, fn func(SearchSeriesResult) error) error {
+	if params.StartRangeBegin.IsZero() {
+		return client.searchSeries(ctx, params, fn)
+	}
+
+	end := params.StartRangeEnd
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	for begin := params.StartRangeBegin; begin.Before(end); begin = begin.Add(SearchSeriesMaxWindow) {
+		windowParams := params
+		windowParams.StartRangeBegin = begin
+		windowParams.StartRangeEnd = begin.Add(SearchSeriesMaxWindow)
+		if windowParams.StartRangeEnd.After(end) {
+			windowParams.StartRangeEnd = end
+		}
+
+		if err := client.searchSeries(ctx, windowParams, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (client *IRacingApiClient) searchSeries(ctx context.Context, params SearchSeriesParams, fn func(SearchSeriesResult) error) error {
+	respBody, err := client.get(ctx, "/data/results/search_series?"+params.query().Encode())
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+
+	response := &searchSeriesResponse{}
+	err = json.NewDecoder(respBody).Decode(response)
+	if err != nil {
+		return err
+	}
+
+	return iterChunks(ctx, client, &response.Data.ChunkInfo, fn)
+}
+
+func (p SearchSeriesParams) query() url.Values {
+	query := url.Values{}
+
+	if p.SeasonYear != 0 {
+		query.Set("season_year", strconv.Itoa(p.SeasonYear))
+	}
+	if p.SeasonQuarter != 0 {
+		query.Set("season_quarter", strconv.Itoa(p.SeasonQuarter))
+	}
+	if !p.StartRangeBegin.IsZero() {
+		query.Set("start_range_begin", formatSearchTime(p.StartRangeBegin))
+	}
+	if !p.StartRangeEnd.IsZero() {
+		query.Set("start_range_end", formatSearchTime(p.StartRangeEnd))
+	}
+	if p.SeriesId != 0 {
+		query.Set("series_id", strconv.Itoa(p.SeriesId))
+	}
+	if p.CustId != 0 {
+		query.Set("cust_id", strconv.Itoa(p.CustId))
+	}
+	if p.TeamId != 0 {
+		query.Set("team_id", strconv.Itoa(p.TeamId))
+	}
+	if p.OfficialOnly {
+		query.Set("official_only", "true")
+	}
+	if len(p.EventTypes) > 0 {
+		query.Set("event_types", joinInts(p.EventTypes))
+	}
+	if len(p.CategoryIds) > 0 {
+		query.Set("category_ids", joinInts(p.CategoryIds))
+	}
+
+	return query
+}
+
+// iRacing expects the times in UTC, without seconds
+func formatSearchTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04Z")
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,49 @@
+package irapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"riccardotornesello.it/sharedtelemetry/iracing/irapi/irapitest"
+)
+
+func TestSearchSeries(t *testing.T) {
+	server := irapitest.NewServer()
+	defer server.Close()
+
+	// The range is longer than the maximum window, so two searches are needed
+	server.AddResponse("/data/results/search_series?start_range_begin=2024-01-01T00:00Z&start_range_end=2024-03-31T00:00Z&series_id=123&official_only=true",
+		map[string]any{"type": "search_series", "data": map[string]any{"success": true, "chunk_info": map[string]any{}}},
+		[]map[string]any{{"subsession_id": 1, "series_id": 123}, {"subsession_id": 2, "series_id": 123}},
+		[]map[string]any{{"subsession_id": 3, "series_id": 123}},
+	)
+	server.AddResponse("/data/results/search_series?start_range_begin=2024-03-31T00:00Z&start_range_end=2024-04-10T00:00Z&series_id=123&official_only=true",
+		map[string]any{"type": "search_series", "data": map[string]any{"success": true, "chunk_info": map[string]any{}}},
+		[]map[string]any{{"subsession_id": 4, "series_id": 123}},
+	)
+
+	client, err := NewIRacingApiClient(context.Background(), NewPasswordAuthenticator("test@example.com", "password"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("irapi.NewIRacingApiClient: %v", err)
+	}
+
+	results, err := client.SearchSeries(context.Background(), SearchSeriesParams{
+		StartRangeBegin: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		StartRangeEnd:   time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		SeriesId:        123,
+		OfficialOnly:    true,
+	})
+	if err != nil {
+		t.Fatalf("client.SearchSeries: %v", err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %+v", results)
+	}
+	for i, result := range results {
+		if result.SubsessionId != i+1 {
+			t.Fatalf("unexpected results order: %+v", results)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package irapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type TrackAssetsResponse struct {
 	Coordinates         string `json:"coordinates"`
@@ -86,12 +89,13 @@ type TrackResponse struct {
 	} `json:"track_types"`
 }
 
-func (client *IRacingApiClient) GetTrackAssets() (*map[string]TrackAssetsResponse, error) {
+func (client *IRacingApiClient) GetTrackAssets(ctx context.Context) (*map[string]TrackAssetsResponse, error) {
 	url := "/data/track/assets"
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := &map[string]TrackAssetsResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
@@ -102,12 +106,13 @@ func (client *IRacingApiClient) GetTrackAssets() (*map[string]TrackAssetsRespons
 	return response, nil
 }
 
-func (client *IRacingApiClient) GetTracks() (*[]TrackResponse, error) {
+func (client *IRacingApiClient) GetTracks(ctx context.Context) (*[]TrackResponse, error) {
 	url := "/data/track/get"
-	respBody, err := client.get(url)
+	respBody, err := client.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	defer respBody.Close()
 
 	response := &[]TrackResponse{}
 	err = json.NewDecoder(respBody).Decode(response)
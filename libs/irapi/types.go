@@ -0,0 +1,163 @@
+package irapi
+
+// Types shared by the responses of several endpoints
+
+type Helmet struct {
+	Pattern    int    `json:"pattern"`
+	Color1     string `json:"color1"`
+	Color2     string `json:"color2"`
+	Color3     string `json:"color3"`
+	FaceType   int    `json:"face_type"`
+	HelmetType int    `json:"helmet_type"`
+}
+
+type Suit struct {
+	Pattern int    `json:"pattern"`
+	Color1  string `json:"color1"`
+	Color2  string `json:"color2"`
+	Color3  string `json:"color3"`
+}
+
+type Livery struct {
+	CarId        int    `json:"car_id"`
+	Pattern      int    `json:"pattern"`
+	Color1       string `json:"color1"`
+	Color2       string `json:"color2"`
+	Color3       string `json:"color3"`
+	NumberFont   int    `json:"number_font"`
+	NumberColor1 string `json:"number_color1"`
+	NumberColor2 string `json:"number_color2"`
+	NumberColor3 string `json:"number_color3"`
+	NumberSlant  int    `json:"number_slant"`
+	Sponsor1     int    `json:"sponsor1"`
+	Sponsor2     int    `json:"sponsor2"`
+	CarNumber    string `json:"car_number"`
+	WheelColor   string `json:"wheel_color"`
+	RimType      int    `json:"rim_type"`
+}
+
+type License struct {
+	CategoryId    int     `json:"category_id"`
+	Category      string  `json:"category"`
+	CategoryName  string  `json:"category_name"`
+	LicenseLevel  int     `json:"license_level"`
+	SafetyRating  float32 `json:"safety_rating"`
+	Cpi           float32 `json:"cpi"`
+	Irating       int     `json:"irating"`
+	TtRating      int     `json:"tt_rating"`
+	MprNumRaces   int     `json:"mpr_num_races"`
+	Color         string  `json:"color"`
+	GroupName     string  `json:"group_name"`
+	GroupId       int     `json:"group_id"`
+	ProPromotable bool    `json:"pro_promotable"`
+	Seq           int     `json:"seq"`
+	MprNumTts     int     `json:"mpr_num_tts"`
+}
+
+// TrackRef identifies a track configuration. The category is not returned by every endpoint.
+type TrackRef struct {
+	Category   string `json:"category"`
+	CategoryId int    `json:"category_id"`
+	ConfigName string `json:"config_name"`
+	TrackId    int    `json:"track_id"`
+	TrackName  string `json:"track_name"`
+}
+
+type TrackState struct {
+	LeaveMarbles         bool `json:"leave_marbles"`
+	PracticeGripCompound int  `json:"practice_grip_compound"`
+	PracticeRubber       int  `json:"practice_rubber"`
+	QualifyGripCompound  int  `json:"qualify_grip_compound"`
+	QualifyRubber        int  `json:"qualify_rubber"`
+	RaceGripCompound     int  `json:"race_grip_compound"`
+	RaceRubber           int  `json:"race_rubber"`
+	WarmupGripCompound   int  `json:"warmup_grip_compound"`
+	WarmupRubber         int  `json:"warmup_rubber"`
+}
+
+// Weather merges the fields of the results (precipitations) and of the league sessions (summary).
+type Weather struct {
+	AllowFog                      bool            `json:"allow_fog"`
+	Fog                           int             `json:"fog"`
+	PrecipMm2hrBeforeFinalSession int             `json:"precip_mm2hr_before_final_session"`
+	PrecipMmFinalSession          int             `json:"precip_mm_final_session"`
+	PrecipOption                  int             `json:"precip_option"`
+	PrecipTimePct                 int             `json:"precip_time_pct"`
+	RelHumidity                   int             `json:"rel_humidity"`
+	SimulatedStartTime            string          `json:"simulated_start_time"`
+	Skies                         int             `json:"skies"`
+	TempUnits                     int             `json:"temp_units"`
+	TempValue                     int             `json:"temp_value"`
+	TimeOfDay                     int             `json:"time_of_day"`
+	TrackWater                    int             `json:"track_water"`
+	Type                          int             `json:"type"`
+	Version                       int             `json:"version"`
+	WeatherSummary                *WeatherSummary `json:"weather_summary"`
+	WeatherVarInitial             int             `json:"weather_var_initial"`
+	WeatherVarOngoing             int             `json:"weather_var_ongoing"`
+	WindDir                       int             `json:"wind_dir"`
+	WindUnits                     int             `json:"wind_units"`
+	WindValue                     int             `json:"wind_value"`
+}
+
+type WeatherSummary struct {
+	MaxPrecipRateDesc string `json:"max_precip_rate_desc"`
+	PrecipChance      int    `json:"precip_chance"`
+}
+
+type CarRef struct {
+	CarId   int    `json:"car_id"`
+	CarName string `json:"car_name"`
+}
+
+type CarClassRef struct {
+	CarClassId  int      `json:"car_class_id"`
+	Name        string   `json:"name"`
+	CarsInClass []CarRef `json:"cars_in_class"`
+}
+
+// ResultsSessionInfo describes the simsession of the results endpoints returning chunks.
+type ResultsSessionInfo struct {
+	SubsessionId          int            `json:"subsession_id"`
+	SessionId             int            `json:"session_id"`
+	SimsessionNumber      int            `json:"simsession_number"`
+	SimsessionType        SimsessionType `json:"simsession_type"`
+	SimsessionName        string         `json:"simsession_name"`
+	NumLapsForQualAverage int            `json:"num_laps_for_qual_average"`
+	NumLapsForSoloAverage int            `json:"num_laps_for_solo_average"`
+	EventType             int            `json:"event_type"`
+	EventTypeName         string         `json:"event_type_name"`
+	PrivateSessionId      int            `json:"private_session_id"`
+	SeasonName            string         `json:"season_name"`
+	SeasonShortName       string         `json:"season_short_name"`
+	SeriesName            string         `json:"series_name"`
+	SeriesShortName       string         `json:"series_short_name"`
+	SessionName           string         `json:"session_name"`
+	RestrictResults       bool           `json:"restrict_results"`
+	StartTime             string         `json:"start_time"`
+	Track                 TrackRef       `json:"track"`
+}
+
+// WeatherResult is the weather measured during a simsession.
+type WeatherResult struct {
+	AvgSkies                 int     `json:"avg_skies"`
+	AvgCloudCoverPct         float64 `json:"avg_cloud_cover_pct"`
+	MinCloudCoverPct         float64 `json:"min_cloud_cover_pct"`
+	MaxCloudCoverPct         float64 `json:"max_cloud_cover_pct"`
+	TempUnits                int     `json:"temp_units"`
+	AvgTemp                  float64 `json:"avg_temp"`
+	MinTemp                  float64 `json:"min_temp"`
+	MaxTemp                  float64 `json:"max_temp"`
+	AvgRelHumidity           float64 `json:"avg_rel_humidity"`
+	WindUnits                int     `json:"wind_units"`
+	AvgWindSpeed             float64 `json:"avg_wind_speed"`
+	MinWindSpeed             float64 `json:"min_wind_speed"`
+	MaxWindSpeed             float64 `json:"max_wind_speed"`
+	AvgWindDir               int     `json:"avg_wind_dir"`
+	MaxFog                   float64 `json:"max_fog"`
+	FogTimePct               float64 `json:"fog_time_pct"`
+	PrecipTimePct            float64 `json:"precip_time_pct"`
+	PrecipMm                 float64 `json:"precip_mm"`
+	PrecipMm2hrBeforeSession float64 `json:"precip_mm2hr_before_session"`
+	SimulatedStartTime       string  `json:"simulated_start_time"`
+}